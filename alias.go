@@ -0,0 +1,71 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Alias adds provider of alias type that resolves provider of target type from container or its parents, target must
+// be assignable or convertible to alias and already provided, aliases can point to other aliases, but can't form a
+// cycle
+func (d *DI) Alias(alias reflect.Type, target reflect.Type) error {
+	if !target.AssignableTo(alias) && !target.ConvertibleTo(alias) {
+		return fmt.Errorf("can't alias type %q to type %q, it's not assignable or convertible",
+			alias.String(), target.String())
+	}
+
+	chain := []providerKey{{pType: alias}}
+	for next := target; ; {
+		chain = append(chain, providerKey{pType: next})
+		if next == alias {
+			return newErrorCycleDetected(chain)
+		}
+
+		p, _, ok := d.lookupProvider(providerKey{pType: next})
+		if !ok {
+			if next == target {
+				return fmt.Errorf("alias of type %q: %w", alias.String(), newErrorProviderNotFound(0,
+					providerKey{pType: target}))
+			}
+			break
+		}
+		if p.aliasOf == nil {
+			break
+		}
+		next = p.aliasOf
+	}
+
+	_, err := d.registerProvider(alias, newAliasProvider(alias, target))
+	return err
+}
+
+// AliasType is like [DI.Alias], but uses type parameters as alias and target types
+func AliasType[Alias, Target any](d *DI) error {
+	return d.Alias(typeOf[Alias](), typeOf[Target]())
+}
+
+// newAliasProvider creates provider of alias type that resolves provider of target type
+func newAliasProvider(alias reflect.Type, target reflect.Type) *provider {
+	p := &provider{
+		aliasOf: target,
+	}
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		key := providerKey{pType: target}
+		targetProvider, owner, ok := di.lookupProvider(key)
+		if !ok {
+			return reflect.Value{}, newErrorProviderNotFound(0, key)
+		}
+
+		res, err := res.push(key)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		value, err := targetProvider.provide(owner, res)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return value.Convert(alias), nil
+	}
+	return p
+}
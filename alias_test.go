@@ -0,0 +1,76 @@
+package mdi
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type testMyReader interface {
+	io.Reader
+	ReadString(delim byte) (string, error)
+}
+
+type testMyString string
+
+func TestDI_Alias(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		reader := bufio.NewReader(strings.NewReader("test"))
+		di := New().MustProvide(reader)
+		if err := AliasType[testMyReader, *bufio.Reader](di); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if err := AliasType[io.Reader, testMyReader](NewFrom(di)); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(r testMyReader, br *bufio.Reader) {
+			if r != br || br != reader {
+				t.Fatalf("unexpected: %v", r)
+			}
+		})
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("success_convert", func(t *testing.T) {
+		di := New().MustProvide("test")
+		if err := AliasType[testMyString, string](di); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(s testMyString) {
+			if s != "test" {
+				t.Fatalf("unexpected: %q", s)
+			}
+		})
+	})
+
+	t.Run("error_missing", func(t *testing.T) {
+		err := AliasType[testMyReader, *bufio.Reader](New())
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_cycle", func(t *testing.T) {
+		di := New().MustProvide("test")
+		if err := AliasType[testMyString, string](di); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		err := AliasType[string, testMyString](di)
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+	})
+
+	t.Run("error_not_assignable", func(t *testing.T) {
+		if err := AliasType[int, string](New().MustProvide("")); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package mdi
+
+// Builder wraps container to chain providing and invoking without checking error after each step, after the first
+// error all further steps are skipped and the error is returned from [Builder.Err]
+type Builder struct {
+	di  *DI
+	err error
+}
+
+// Builder returns new builder of container
+func (d *DI) Builder() *Builder {
+	return &Builder{di: d}
+}
+
+// Provide calls [DI.Provide] if no error occurred before
+func (b *Builder) Provide(provide any, options ...ProviderOption) *Builder {
+	if b.err == nil {
+		b.err = b.di.Provide(provide, options...)
+	}
+	return b
+}
+
+// Invoke calls [DI.Invoke] if no error occurred before
+func (b *Builder) Invoke(functions ...any) *Builder {
+	if b.err == nil {
+		b.err = b.di.Invoke(functions...)
+	}
+	return b
+}
+
+// Err returns the first error occurred while building
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// DI returns wrapped container
+func (b *Builder) DI() *DI {
+	return b.di
+}
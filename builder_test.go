@@ -0,0 +1,51 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_Builder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		called := false
+		di := New()
+		err := di.Builder().
+			Provide(1).
+			Provide(func(i int) string { return "ok" }).
+			Invoke(func(s string) {
+				called = true
+				if s != "ok" {
+					t.Fatalf("unexpected: %q", s)
+				}
+			}).
+			Err()
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if !called {
+			t.Fatalf("function should be called")
+		}
+		if !HasType[string](di) {
+			t.Fatalf("expected provider")
+		}
+	})
+
+	t.Run("error_short_circuit", func(t *testing.T) {
+		called := false
+		b := New().Builder().
+			Provide(1).
+			Provide(2).
+			Provide(func(i int) string { return "" }).
+			Invoke(func() { called = true })
+
+		if err := b.Err(); !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if called {
+			t.Fatalf("function should not be called")
+		}
+		if HasType[string](b.DI()) {
+			t.Fatalf("unexpected provider")
+		}
+	})
+}
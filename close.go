@@ -0,0 +1,68 @@
+package mdi
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// cleanupType represents type of cleanup function returned by constructors
+var cleanupType = reflect.TypeOf((func())(nil))
+
+// cleanupEntry represents cleanup function alongside with its phase, see [WithCleanupPhase]
+type cleanupEntry struct {
+	cleanup func()
+	phase   int
+}
+
+// Close calls cleanup functions returned by constructors of container's providers grouped by phase (lower phases
+// first, see [WithCleanupPhase]) and in reverse order of construction within a phase, each cleanup is called only
+// once, constructors with cleanup have signature func(...) (T, func()) or func(...) (T, func(), error), cleanup itself
+// isn't registered as a provider
+func (d *DI) Close() error {
+	if d.target != nil {
+		return d.target.Close()
+	}
+
+	d.cleanupMutex.Lock()
+	cleanups := d.cleanups
+	d.cleanups = nil
+	d.cleanupMutex.Unlock()
+
+	slices.Reverse(cleanups)
+	sort.SliceStable(cleanups, func(i, j int) bool {
+		return cleanups[i].phase < cleanups[j].phase
+	})
+
+	for _, entry := range cleanups {
+		entry.cleanup()
+	}
+	return nil
+}
+
+// addCleanup adds cleanup function that will be called on [DI.Close] in the phase
+func (d *DI) addCleanup(cleanup func(), phase int) {
+	if cleanup == nil {
+		return
+	}
+	if d.target != nil {
+		d.target.addCleanup(cleanup, phase)
+		return
+	}
+
+	d.cleanupMutex.Lock()
+	d.cleanups = append(d.cleanups, cleanupEntry{cleanup: cleanup, phase: phase})
+	d.cleanupMutex.Unlock()
+}
+
+// isCleanupConstructor reports whether function returns value with cleanup function (and optionally an error)
+func isCleanupConstructor(fType reflect.Type) bool {
+	switch fType.NumOut() {
+	case 2:
+		return !isTypeErr(fType.Out(0)) && fType.Out(1) == cleanupType
+	case 3:
+		return !isTypeErr(fType.Out(0)) && fType.Out(1) == cleanupType && isTypeErr(fType.Out(2))
+	default:
+		return false
+	}
+}
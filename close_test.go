@@ -0,0 +1,142 @@
+package mdi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestDI_Close(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var cleaned []string
+		di := New().
+			MustProvide(func() (int, func()) {
+				return 1, func() { cleaned = append(cleaned, "int") }
+			}).
+			MustProvide(func(i int) (string, func(), error) {
+				return "ok", func() { cleaned = append(cleaned, "string") }, nil
+			})
+
+		if HasType[func()](di) {
+			t.Fatalf("unexpected cleanup provider")
+		}
+
+		di.MustInvoke(func(s string, i int) {
+			if s != "ok" || i != 1 {
+				t.Fatalf("unexpected: %q, %d", s, i)
+			}
+		})
+
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if len(cleaned) != 2 || cleaned[0] != "string" || cleaned[1] != "int" {
+			t.Fatalf("unexpected: %v", cleaned)
+		}
+	})
+
+	t.Run("entry_points", func(t *testing.T) {
+		constructor := func(cleaned *int) func() (*bytes.Buffer, func(), error) {
+			return func() (*bytes.Buffer, func(), error) {
+				return &bytes.Buffer{}, func() { *cleaned++ }, nil
+			}
+		}
+		provides := map[string]func(di *DI, cleaned *int) error{
+			"provide_as": func(di *DI, cleaned *int) error {
+				return di.ProvideAs((*io.Writer)(nil), constructor(cleaned))
+			},
+			"provide_as_many": func(di *DI, cleaned *int) error {
+				return di.ProvideAsMany(constructor(cleaned), []any{(*io.Writer)(nil), (*io.Reader)(nil)})
+			},
+			"provide_generic": func(di *DI, cleaned *int) error {
+				return ProvideGeneric[io.Writer](di, constructor(cleaned))
+			},
+		}
+
+		for name, provide := range provides {
+			t.Run(name, func(t *testing.T) {
+				cleaned := 0
+				di := New()
+				if err := provide(di, &cleaned); err != nil {
+					t.Fatalf("unexpected error: %q", err)
+				}
+				if HasType[func()](di) {
+					t.Fatalf("unexpected cleanup provider")
+				}
+
+				di.MustInvoke(func(w io.Writer) {})
+				if err := di.Close(); err != nil {
+					t.Fatalf("unexpected error: %q", err)
+				}
+				if cleaned != 1 {
+					t.Fatalf("unexpected: %d", cleaned)
+				}
+			})
+		}
+	})
+
+	t.Run("phases", func(t *testing.T) {
+		var cleaned []string
+		di := New().
+			MustProvide(func() (int, func()) {
+				return 1, func() { cleaned = append(cleaned, "db") }
+			}, WithCleanupPhase(1)).
+			MustProvide(func(i int) (string, func()) {
+				return "ok", func() { cleaned = append(cleaned, "cache") }
+			}, WithCleanupPhase(1)).
+			MustProvide(func(s string) (bool, func()) {
+				return true, func() { cleaned = append(cleaned, "server") }
+			})
+
+		di.MustInvoke(func(b bool) {})
+
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if expected := []string{"server", "cache", "db"}; !slices.Equal(cleaned, expected) {
+			t.Fatalf("unexpected: %v", cleaned)
+		}
+	})
+
+	t.Run("phases_override_order", func(t *testing.T) {
+		var cleaned []string
+		di := New().
+			MustProvide(func() (int, func()) {
+				return 1, func() { cleaned = append(cleaned, "server") }
+			}).
+			MustProvide(func(i int) (string, func()) {
+				return "ok", func() { cleaned = append(cleaned, "db") }
+			}, WithCleanupPhase(1))
+
+		di.MustInvoke(func(s string) {})
+
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if expected := []string{"server", "db"}; !slices.Equal(cleaned, expected) {
+			t.Fatalf("unexpected: %v", cleaned)
+		}
+	})
+
+	t.Run("error_constructor", func(t *testing.T) {
+		cleaned := false
+		di := New().MustProvide(func() (int, func(), error) {
+			return 0, func() { cleaned = true }, errTest
+		})
+
+		if err := di.Invoke(func(i int) {}); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if cleaned {
+			t.Fatalf("cleanup should not be called")
+		}
+	})
+}
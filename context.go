@@ -0,0 +1,33 @@
+package mdi
+
+import (
+	"context"
+	"reflect"
+)
+
+// contextKey represents key of [context.Context]
+var contextKey = providerKey{pType: typeOf[context.Context]()}
+
+// InvokeContext is like [DI.Invoke], but context is available as a dependency of [context.Context] type (if there is
+// no provider for it) to functions and constructors called during resolution, if context is canceled, constructors
+// that are still running are abandoned (they are expected to return on context cancellation) and context error is
+// returned, values of abandoned constructors are discarded
+func (d *DI) InvokeContext(ctx context.Context, functions ...any) error {
+	for _, function := range functions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res := newResolution(d)
+		res.ctx = ctx
+		if _, err := d.invoke(function, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contextValue returns context as value of [context.Context] type
+func contextValue(ctx context.Context) reflect.Value {
+	return reflect.ValueOf(&ctx).Elem()
+}
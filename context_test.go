@@ -0,0 +1,109 @@
+package mdi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDI_InvokeContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("inject", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		di := New().MustProvide(func(ctx context.Context) string { return ctx.Value(ctxKey{}).(string) })
+
+		if err := di.InvokeContext(ctx, func(c context.Context, s string) {
+			if c != ctx {
+				t.Fatal("unexpected context")
+			}
+			if s != "value" {
+				t.Fatalf("unexpected: %q", s)
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("provider", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "provided")
+		di := New()
+		if err := ProvideValue[context.Context](di, ctx); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if err := di.InvokeContext(context.Background(), func(c context.Context) {
+			if c.Value(ctxKey{}) != "provided" {
+				t.Fatal("unexpected context")
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("without_context", func(t *testing.T) {
+		di := New()
+		if err := di.Invoke(func(ctx context.Context) {}); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		called := false
+		di := New()
+		if err := di.InvokeContext(ctx, func() { called = true }); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected error: %q, but got: %v", context.Canceled, err)
+		}
+		if called {
+			t.Fatal("unexpected call")
+		}
+	})
+
+	t.Run("cancel_construction", func(t *testing.T) {
+		started := make(chan struct{})
+		returned := make(chan struct{})
+		constructed := 0
+
+		di := New().MustProvide(func(ctx context.Context) (int, error) {
+			constructed++
+			if constructed > 1 {
+				return 1, nil
+			}
+
+			defer close(returned)
+			close(started)
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+
+		if err := di.InvokeContext(ctx, func(int) {
+			t.Fatal("unexpected call")
+		}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected error: %q, but got: %v", context.Canceled, err)
+		}
+
+		select {
+		case <-returned:
+		case <-time.After(time.Second):
+			t.Fatal("constructor didn't return")
+		}
+
+		if err := di.InvokeContext(context.Background(), func(i int) {
+			if i != 1 {
+				t.Fatalf("unexpected: %d", i)
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+}
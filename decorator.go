@@ -0,0 +1,122 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decorator represents function that decorates provided value
+type decorator struct {
+	function    any
+	valueIndex  int
+	resultIndex int
+}
+
+// Decorate adds decorator to provider of the type decorator returns, decorator is a function that takes current value
+// of that type (and optionally other dependencies) and returns a new value of the same type (and optionally an error),
+// decorators are applied in registration order before value is cached
+func (d *DI) Decorate(decorator any) error {
+	dec, pType, err := newDecorator(decorator)
+	if err != nil {
+		return err
+	}
+
+	key := providerKey{pType: pType}
+	p, ok := d.getProvider(key)
+	if !ok {
+		return fmt.Errorf("can't decorate, %w", newErrorProviderNotFound(dec.valueIndex, key))
+	}
+	if p.useRoundRobin {
+		return fmt.Errorf("can't decorate round-robin provider of type %q", pType.String())
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.value.IsValid() && p.cache.IsValid() {
+		return fmt.Errorf("can't decorate already instantiated provider of type %q", pType.String())
+	}
+
+	p.decorators = append(p.decorators, dec)
+	p.cache = reflect.Value{}
+	return nil
+}
+
+// newDecorator creates a new decorator and returns type it decorates
+func newDecorator(function any) (*decorator, reflect.Type, error) {
+	fType := reflect.TypeOf(function)
+	if fType == nil || fType.Kind() != reflect.Func || reflect.ValueOf(function).IsNil() {
+		return nil, nil, newErrorNotAFunction()
+	}
+
+	dec := &decorator{
+		function:    function,
+		valueIndex:  -1,
+		resultIndex: -1,
+	}
+
+	var pType reflect.Type
+	for i := 0; i < fType.NumOut(); i++ {
+		if isTypeErr(fType.Out(i)) {
+			continue
+		}
+		if pType != nil {
+			return nil, nil, fmt.Errorf("can't decorate with %q, must return exactly one value", fType.String())
+		}
+		pType = fType.Out(i)
+		dec.resultIndex = i
+	}
+	if pType == nil {
+		return nil, nil, fmt.Errorf("can't decorate with %q, must return exactly one value", fType.String())
+	}
+
+	for i := 0; i < fType.NumIn(); i++ {
+		if fType.In(i) == pType {
+			dec.valueIndex = i
+			break
+		}
+	}
+	if dec.valueIndex == -1 {
+		return nil, nil, fmt.Errorf("can't decorate with %q, must accept value of type %q",
+			fType.String(), pType.String())
+	}
+
+	return dec, pType, nil
+}
+
+// decorate applies all decorators of provider to value
+func (p *provider) decorate(di *DI, res *resolution, value reflect.Value) (reflect.Value, error) {
+	p.mutex.RLock()
+	decorators := p.decorators
+	p.mutex.RUnlock()
+
+	for _, dec := range decorators {
+		fValue := reflect.ValueOf(dec.function)
+		fType := fValue.Type()
+
+		call := res.forCall()
+		paramValues := make([]reflect.Value, 0, fType.NumIn())
+		for i := 0; i < fType.NumIn(); i++ {
+			if i == dec.valueIndex {
+				paramValues = append(paramValues, value)
+				continue
+			}
+
+			paramValue, err := di.invokeParam(fType.In(i), i, call)
+			if err != nil {
+				call.unbind()
+				return reflect.Value{}, err
+			}
+			paramValues = append(paramValues, paramValue)
+		}
+
+		results, err := functionCall(fValue, paramValues, res.settings.recoverPanics)
+		call.unbind()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value = results[dec.resultIndex]
+	}
+
+	return value, nil
+}
@@ -0,0 +1,81 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDI_Decorate(t *testing.T) {
+	t.Run("success_order", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() int { calls++; return 1 })
+
+		if err := di.Decorate(func(i int) int { return i + 1 }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if err := di.Decorate(func(i int) (int, error) { return i * 10, nil }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(i1, i2 int) {
+			if i1 != 20 || i2 != 20 {
+				t.Fatalf("unexpected: %d %d", i1, i2)
+			}
+		})
+		if calls != 1 {
+			t.Fatalf("expected one call, but got: %d", calls)
+		}
+	})
+
+	t.Run("success_value_with_deps", func(t *testing.T) {
+		di := New().MustProvide("a").MustProvide(2)
+
+		if err := di.Decorate(func(s string, i int) string { return s + "b" }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(s string) {
+			if s != "ab" {
+				t.Fatalf("unexpected: %q", s)
+			}
+		})
+	})
+
+	t.Run("error_decorator", func(t *testing.T) {
+		di := New().MustProvide(1)
+		if err := di.Decorate(func(i int) (int, error) { return 0, errTest }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if err := di.Invoke(func(i int) {}); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		err := New().Decorate(func(i int) int { return i })
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_invalid", func(t *testing.T) {
+		di := New().MustProvide(1)
+		if err := di.Decorate(func() int { return 1 }); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := di.Decorate(func(i int) {}); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := di.Decorate(1); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+
+	t.Run("error_instantiated", func(t *testing.T) {
+		di := New().MustProvide(func() int { return 1 }).MustInvoke(func(i int) {})
+		if err := di.Decorate(func(i int) int { return i }); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
@@ -1,40 +1,132 @@
 package mdi
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// diKey represents key of [DI] itself
+var diKey = providerKey{pType: reflect.TypeOf((*DI)(nil))}
+
 // New creates [DI] container
-func New() *DI {
-	return NewFrom(nil)
+func New(options ...Option) *DI {
+	return NewFrom(nil, options...)
 }
 
 // NewFrom creates new [DI] container with parent (base) container
-func NewFrom(parent *DI) *DI {
+func NewFrom(parent *DI, options ...Option) *DI {
 	di := &DI{
-		parent:       parent,
-		provide:      provideMap{},
-		provideMutex: sync.RWMutex{},
+		parent: parent,
+	}
+	if len(options) != 0 {
+		_ = di.update(func(s *state) error {
+			for _, option := range options {
+				option(s)
+			}
+			return nil
+		})
 	}
 	return di.MustProvide(di)
 }
 
-// DI represents dependency container
+// Scope creates new child [DI] container of container, same as [NewFrom] with container as parent
+func (d *DI) Scope() *DI {
+	return NewFrom(d)
+}
+
+// WithValue creates new child [DI] container of container with value provided under its type, so value can be
+// resolved only in that scope (e.g. for per-request data), functions are provided as values too (not as constructors)
+func (d *DI) WithValue(value any) (*DI, error) {
+	pValue := reflect.ValueOf(value)
+	if !pValue.IsValid() {
+		return nil, errors.New("can't provide nil value")
+	}
+
+	scope := d.Scope()
+	if err := scope.addValue(pValue.Type(), pValue, nil); err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+// MustWithValue is like [DI.WithValue], but panics if error occurs
+func (d *DI) MustWithValue(value any) *DI {
+	scope, err := d.WithValue(value)
+	if err != nil {
+		panic(err)
+	}
+	return scope
+}
+
+// Parent returns parent container or nil if container has no parent
+func (d *DI) Parent() *DI {
+	return d.parent
+}
+
+// DI represents dependency container, zero value is ready to use empty container (the same as created by [New],
+// except that container itself isn't registered as a provider, [DI] is still resolved as resolving container)
 type DI struct {
-	parent       *DI
-	provide      provideMap
-	provideMutex sync.RWMutex
+	parent *DI
+	// target is container that this container is a view of and binding is resolution view is bound to, see [DI.view]
+	target            *DI
+	binding           *boundResolver
+	state             atomic.Pointer[state]
+	provideMutex      sync.Mutex
+	cleanupMutex      sync.Mutex
+	cleanups          []cleanupEntry
+	parentLinks       sync.Map
+	assignableMatches sync.Map
 }
 
-// Provide adds provider to container or returns error if the value can't be represented as provider
+// Provide adds provider to container or returns error if the value can't be represented as provider, values are
+// registered under their dynamic type, so interface value (e.g. io.Reader(os.Stdin)) is registered under its concrete
+// type (*os.File), use [WithStaticType] or [ProvideValue] to register it under interface type
 func (d *DI) Provide(provide any, options ...ProviderOption) error {
 	pValue := reflect.ValueOf(provide)
+	if !pValue.IsValid() {
+		return errors.New("can't provide nil")
+	}
+	if newProviderFromOptions(options).staticType {
+		if pValue.Kind() != reflect.Ptr || pValue.IsNil() {
+			return fmt.Errorf("can't provide %T with static type, must be a non-nil pointer", provide)
+		}
+		return d.provideValue(pValue.Type().Elem(), pValue.Elem(), options)
+	}
 	if pValue.Kind() == reflect.Func {
 		return d.provideFunction(provide, options)
 	}
-	return d.provideValue(pValue, options)
+	return d.provideValue(pValue.Type(), pValue, options)
+}
+
+// ProvideAll calls [DI.Provide] for each value, stops at the first error and returns it with index and type of value
+// that failed, values provided before the error stay in container
+func (d *DI) ProvideAll(provides ...any) error {
+	for i, provide := range provides {
+		if err := d.Provide(provide); err != nil {
+			return fmt.Errorf("provide %d (%T): %w", i, provide, err)
+		}
+	}
+	return nil
+}
+
+// ProvideValues adds value provider for each value under its dynamic type, functions are provided as values too
+// (see [DI.ProvideFuncValue]), stops at the first error and returns it with index and type of value that failed,
+// values provided before the error stay in container
+func (d *DI) ProvideValues(values ...any) error {
+	for i, value := range values {
+		pValue := reflect.ValueOf(value)
+		if !pValue.IsValid() {
+			return fmt.Errorf("provide value %d: can't provide nil", i)
+		}
+		if err := d.provideValue(pValue.Type(), pValue, nil); err != nil {
+			return fmt.Errorf("provide value %d (%T): %w", i, value, err)
+		}
+	}
+	return nil
 }
 
 // MustProvide is like [DI.Provide], but panics if error occurs
@@ -45,16 +137,138 @@ func (d *DI) MustProvide(value any, options ...ProviderOption) *DI {
 	return d
 }
 
-// Invoke calls functions with dependencies provided from the container
+// ProvideFuncValue adds function itself as value provider under its function type, unlike [DI.Provide] that treats
+// functions as constructors, so function can be injected as a callback (e.g. func(int) string)
+func (d *DI) ProvideFuncValue(fn any, options ...ProviderOption) error {
+	fValue := reflect.ValueOf(fn)
+	if !fValue.IsValid() || fValue.Kind() != reflect.Func {
+		return newErrorNotAFunction()
+	}
+	if fValue.IsNil() {
+		return errors.New("can't provide nil function")
+	}
+	return d.provideValue(fValue.Type(), fValue, options)
+}
+
+// ProvideDefault is like [DI.Provide], but adds provider only as default of its type, default is ignored if provider
+// of the same type already exists and is silently replaced by any later non-default provider, default also yields to
+// non-default providers of the same type from parents
+func (d *DI) ProvideDefault(provide any, options ...ProviderOption) error {
+	return d.Provide(provide, append(options, asDefault())...)
+}
+
+// ProvideOnce is like [DI.Provide], but returns false without an error if provider of the same type (and name)
+// already exists in container, even if override is allowed, for functions with multiple return values nothing is
+// added if any of them exists
+func (d *DI) ProvideOnce(provide any, options ...ProviderOption) (bool, error) {
+	err := d.Provide(provide, append(options, asOnce())...)
+	if errors.Is(err, ErrProviderAlreadyExists) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Shadow is like [DI.Provide], but is meant for child containers to intentionally replace provider of parent,
+// container always resolves its own providers before providers of parents, so shadowing provider is used in container
+// and its children, while parent and sibling containers keep using the original one, note that providers of parents
+// resolve their own dependencies from the container that owns them, so they aren't affected by shadowing, returns an
+// error if container has no parent
+func (d *DI) Shadow(provide any, options ...ProviderOption) error {
+	if d.parent == nil {
+		return fmt.Errorf("can't shadow %T in container without parent", provide)
+	}
+	return d.Provide(provide, options...)
+}
+
+// Clone creates a copy of container with the same parent and copies of all its providers, cached values of function
+// providers are not copied, so they will be constructed again in the cloned container
+func (d *DI) Clone() *DI {
+	s := d.load()
+	clone := &DI{
+		parent: d.parent,
+	}
+	cloneState := &state{
+		observer:       s.observer,
+		eager:          s.eager,
+		assignable:     s.assignable,
+		maxDepth:       s.maxDepth,
+		allowOverride:  s.allowOverride,
+		onProvide:      s.onProvide,
+		recoverPanics:  s.recoverPanics,
+		stats:          s.stats,
+		linkCache:      s.linkCache,
+		defaultOptions: s.defaultOptions,
+	}
+
+	clones := make(map[*provider]*provider, s.provide.len())
+	cloneProvider := func(p *provider) *provider {
+		c, ok := clones[p]
+		if !ok {
+			c = p.clone()
+			clones[p] = c
+		}
+		return c
+	}
+
+	s.provide.each(func(key providerKey, p *provider) {
+		cloneState.provide = cloneState.provide.with(key, cloneProvider(p))
+	})
+	s.groups.each(func(pType reflect.Type, members []*provider) {
+		for _, p := range members {
+			cloneState.groups = cloneState.groups.with(pType, cloneProvider(p))
+		}
+	})
+	s.keyed.each(func(pType reflect.Type, members []*provider) {
+		for _, p := range members {
+			cloneState.keyed = cloneState.keyed.with(pType, cloneProvider(p))
+		}
+	})
+	for _, c := range clones {
+		if c.collectionOf != nil {
+			c.collectionOf = cloneProvider(c.collectionOf)
+		}
+	}
+
+	cloneState.provide = cloneState.provide.with(diKey,
+		newProviderFromOptions(nil).setStrategyByValue(reflect.ValueOf(clone)))
+	clone.state.Store(cloneState)
+	return clone
+}
+
+// Invoke calls functions with dependencies provided from the container, functions may add providers while being called
 func (d *DI) Invoke(functions ...any) error {
 	for _, function := range functions {
-		if _, err := d.invoke(function); err != nil {
+		if _, err := d.invoke(function, newResolution(d)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// InvokeFresh is like [DI.Invoke], but all function providers needed for functions are constructed again ignoring
+// their cached values (once per function), newly constructed values are not cached, so cached values stay unchanged
+func (d *DI) InvokeFresh(functions ...any) error {
+	for _, function := range functions {
+		res := newResolution(d)
+		res.fresh = &freshCache{values: map[*provider]reflect.Value{}}
+		if _, err := d.invoke(function, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvokeAll is like [DI.Invoke], but calls all functions even if some of them fail, returns all errors joined
+func (d *DI) InvokeAll(functions ...any) error {
+	var errs []error
+	for _, function := range functions {
+		if _, err := d.invoke(function, newResolution(d)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // MustInvoke is like [DI.Invoke], but panics if error occurs
 func (d *DI) MustInvoke(functions ...any) *DI {
 	if err := d.Invoke(functions...); err != nil {
@@ -63,118 +277,492 @@ func (d *DI) MustInvoke(functions ...any) *DI {
 	return d
 }
 
-// addProvider adds a provider by type to container
-func (d *DI) addProvider(pType reflect.Type, p *provider) error {
-	d.provideMutex.Lock()
+// Has checks if the provider of type exists in container or any of its parents (for slices group of element type
+// is considered as well), [Resolver] and [Provider] are always provided by container
+func (d *DI) Has(pType reflect.Type) bool {
+	return d.hasKey(providerKey{pType: pType})
+}
 
-	if _, ok := d.provide[pType]; ok {
-		d.provideMutex.Unlock()
-		return newErrorProviderAlreadyExists(pType)
+// hasKey checks if the provider of key exists in container or any of its parents, see [DI.Has]
+func (d *DI) hasKey(key providerKey) bool {
+	if _, _, ok := d.lookupProvider(key); ok || isContainerKey(key) {
+		return true
 	}
+	_, _, ok := d.lookupGroup(key)
+	return ok
+}
 
-	d.provide[pType] = p
-	d.provideMutex.Unlock()
-	return nil
+// HasType is like [DI.Has], but uses type parameter as a provider type
+func HasType[T any](d *DI) bool {
+	return d.Has(typeOf[T]())
 }
 
-// getProvider returns provider by type from container
-func (d *DI) getProvider(pType reflect.Type) (*provider, bool) {
-	d.provideMutex.RLock()
-	p, ok := d.provide[pType]
-	d.provideMutex.RUnlock()
-	return p, ok
+// Reset clears cached value of function provider of type from container, so it will be constructed again on next
+// resolution, returns false if there is no such provider or it's a value provider
+func (d *DI) Reset(pType reflect.Type) bool {
+	p, ok := d.getProvider(providerKey{pType: pType})
+	if !ok {
+		return false
+	}
+	return p.reset()
 }
 
-// canAddProvider check if provider can be added
-func (d *DI) canAddProvider(pType reflect.Type) (bool, error) {
-	if isTypeErr(pType) {
-		return false, nil
+// ResetAll clears cached values of all function providers of container (without parents), so they will be
+// constructed again on next use, registrations and value providers are left unchanged, cleanups of already constructed
+// values are called (see [DI.Close]), since these values are no longer used by container
+func (d *DI) ResetAll() {
+	s := d.load()
+	reset := make(map[*provider]bool, s.provide.len())
+	resetProvider := func(p *provider) {
+		if !reset[p] {
+			reset[p] = true
+			p.reset()
+		}
+	}
+
+	s.provide.each(func(_ providerKey, p *provider) {
+		resetProvider(p)
+	})
+	resetMembers := func(_ reflect.Type, members []*provider) {
+		for _, p := range members {
+			resetProvider(p)
+		}
+	}
+	s.groups.each(resetMembers)
+	s.keyed.each(resetMembers)
+
+	_ = d.Close()
+}
+
+// ResetType is like [DI.Reset], but uses type parameter as a provider type
+func ResetType[T any](d *DI) bool {
+	return d.Reset(typeOf[T]())
+}
+
+// ReplaceConstructor replaces constructor of function provider of type in container (without parents), already
+// constructed value stays cached and is returned until provider is reset (see [DI.Reset]), only then new constructor
+// is called, constructor has to return value of provider type
+func (d *DI) ReplaceConstructor(pType reflect.Type, constructor any) error {
+	p, ok := d.getProvider(providerKey{pType: pType})
+	if !ok {
+		return fmt.Errorf("can't replace constructor, %w", newErrorProviderNotFound(0, providerKey{pType: pType}))
+	}
+	if !p.isFunction() || p.useRoundRobin {
+		return fmt.Errorf("can't replace constructor of %q, only function providers without round-robin are supported",
+			pType.String())
+	}
+
+	cValue := reflect.ValueOf(constructor)
+	if cValue.Kind() != reflect.Func || cValue.IsNil() {
+		return fmt.Errorf("can't replace constructor of %q with non-function %T", pType.String(), constructor)
+	}
+
+	cType := cValue.Type()
+	if len(p.paramNames) > cType.NumIn() {
+		return fmt.Errorf("can't use %d parameter names for function %q with %d parameters",
+			len(p.paramNames), cType.String(), cType.NumIn())
+	}
+	for i := 0; i < cType.NumOut(); i++ {
+		if cType.Out(i) == pType {
+			p.replaceFunction(constructor, i, isCleanupConstructor(cType))
+			return nil
+		}
 	}
-	if _, ok := d.getProvider(pType); ok {
-		return false, newErrorProviderAlreadyExists(pType)
+	return fmt.Errorf("can't replace constructor of %q with function %q that doesn't return it",
+		pType.String(), cType.String())
+}
+
+// addProvider adds a provider by type (and provider's name) to state, returns false if provider was ignored because
+// existing provider has higher priority
+func (s *state) addProvider(pType reflect.Type, p *provider) (bool, error) {
+	key := p.key(pType)
+	if key.custom != nil && !reflect.TypeOf(key.custom).Comparable() {
+		return false, fmt.Errorf("can't use custom key of type %T, must be comparable", key.custom)
 	}
+	if existing, ok := s.provide.get(key); ok {
+		if ok, err := canReplaceProvider(key, existing, p, s.allowOverride); !ok {
+			return false, err
+		}
+	}
+
+	s.provide = s.provide.with(key, p)
 	return true, nil
 }
 
-// provideValue adds value provider to container
-func (d *DI) provideValue(pValue reflect.Value, options []ProviderOption) error {
-	pType := pValue.Type()
-	if ok, err := d.canAddProvider(pType); err != nil {
-		return err
-	} else if !ok {
+// getProvider returns provider by key from container
+func (d *DI) getProvider(key providerKey) (*provider, bool) {
+	return d.load().provide.get(key)
+}
+
+// lookupProvider returns provider by key from container or any of its parents alongside with container that owns it,
+// inactive providers are skipped (see [WithConditional]) and defaults yield to parents (see [DI.ProvideDefault])
+func (d *DI) lookupProvider(key providerKey) (*provider, *DI, bool) {
+	if p, ok := d.getProvider(key); ok && p.isActive() {
+		if p.isDefault && d.parent != nil {
+			if parentP, owner, found := d.parent.lookupProvider(key); found && !parentP.isDefault {
+				return parentP, owner, true
+			}
+		}
+		return p, d, true
+	}
+	if d.parent != nil && d.load().linkCache {
+		return d.lookupParentLink(key)
+	}
+	if d.parent != nil {
+		return d.parent.lookupProvider(key)
+	}
+	return nil, nil, false
+}
+
+// providers returns all providers of container
+func (d *DI) providers() provideMap {
+	return d.load().provide
+}
+
+// registration represents provider that has to be registered under type
+type registration struct {
+	pType    reflect.Type
+	provider *provider
+}
+
+// registerProvider adds provider to container, to the group of type if provider is a group member or to the keyed
+// collection of type if provider has a key, returns false if provider was ignored because of priority
+func (d *DI) registerProvider(pType reflect.Type, p *provider) (bool, error) {
+	added, err := d.registerProviders([]registration{{pType: pType, provider: p}})
+	if err != nil {
+		return false, err
+	}
+	return added[0], nil
+}
+
+// registerProviders adds all providers to container at once, if any of them can't be added none of them are added,
+// returns for each provider whether it was added or ignored because of priority, see [DI.registerProvider]
+func (d *DI) registerProviders(registrations []registration) ([]bool, error) {
+	if onProvide := d.getOnProvide(); onProvide != nil {
+		for _, r := range registrations {
+			if r.pType == diKey.pType {
+				continue
+			}
+			if err := onProvide(r.pType); err != nil {
+				return nil, fmt.Errorf("provider of type %q rejected: %w", r.pType.String(), err)
+			}
+		}
+	}
+
+	added := make([]bool, len(registrations))
+	err := d.update(func(s *state) error {
+		for i, r := range registrations {
+			var err error
+			if added[i], err = s.register(r.pType, r.provider); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// register adds provider to state, see [DI.registerProvider]
+func (s *state) register(pType reflect.Type, p *provider) (bool, error) {
+	if p.group {
+		s.addGroupMember(pType, p)
+		return true, nil
+	}
+	if p.mapKey != nil {
+		return true, s.addKeyedMember(pType, p)
+	}
+	return s.addProvider(pType, p)
+}
+
+// canAddProvider check if provider can be added, returns false if provider should be ignored because existing
+// provider has higher priority
+func (d *DI) canAddProvider(pType reflect.Type, p *provider) (bool, error) {
+	if p.group {
+		return true, nil
+	}
+	if p.mapKey != nil {
+		return true, d.canAddKeyedMember(pType, p)
+	}
+
+	key := p.key(pType)
+	existing, ok := d.getProvider(key)
+	if !ok {
+		return true, nil
+	}
+	return canReplaceProvider(key, existing, p, d.load().allowOverride)
+}
+
+// canReplaceProvider checks if existing provider can be replaced by a new one based on their priorities, if override
+// is allowed providers with equal priorities are replaced instead of conflicting, defaults always yield to other
+// providers, see [DI.ProvideDefault]
+func canReplaceProvider(key providerKey, existing *provider, p *provider, allowOverride bool) (bool, error) {
+	switch {
+	case existing.isDefault && !p.isDefault:
+		return true, nil
+	case p.isDefault:
+		return false, nil
+	case p.once:
+		return false, newErrorProviderAlreadyExists(key)
+	case p.priority > existing.priority:
+		return true, nil
+	case p.priority < existing.priority:
+		return false, nil
+	case allowOverride:
+		return true, nil
+	default:
+		return false, newErrorProviderAlreadyExists(key)
+	}
+}
+
+// provideValue adds value provider of type to container, default options are applied to all values except container
+// itself, see [DI.SetDefaultOptions]
+func (d *DI) provideValue(pType reflect.Type, pValue reflect.Value, options []ProviderOption) error {
+	if pType != diKey.pType {
+		options = d.withDefaultOptions(options)
+	}
+	return d.addValue(pType, pValue, options)
+}
+
+// addValue adds value provider of type to container without applying default options, so values added by container
+// itself (e.g. by [DI.WithValue]) aren't affected by them
+func (d *DI) addValue(pType reflect.Type, pValue reflect.Value, options []ProviderOption) error {
+	if isTypeErr(pType) {
 		return fmt.Errorf("can't provide value of type %q", pType.String())
 	}
 
-	var err error
 	p := newProviderFromOptions(options)
+	if err := p.checkWeights(pType); err != nil {
+		return err
+	}
+	if p.copy && (p.useRoundRobin || !canCopy(pType)) {
+		return fmt.Errorf("can't provide value of type %q with copy", pType.String())
+	}
 	if p.useRoundRobin {
-		if eType, ok := elementType(pType); ok {
-			err = d.addProvider(eType, p.setStrategyByValueRoundRobin(pValue))
-		} else {
-			err = newErrorProviderCantRoundRobin(pType)
+		eType, err := roundRobinElementType(pType, p)
+		if err != nil {
+			return err
 		}
-	} else {
-		err = d.addProvider(pType, p.setStrategyByValue(pValue))
+		values, err := p.roundRobinValues(pValue)
+		if err != nil {
+			return err
+		}
+		if !values.IsValid() || values.Len() == 0 {
+			return newErrorEmptyRoundRobin(pType)
+		}
+		if p.weights != nil && len(p.weights) != values.Len() {
+			return newErrorRoundRobinWeights(pType, len(p.weights), values.Len())
+		}
+
+		collection := newProviderFromOptions([]ProviderOption{WithName(p.name), asDefault()})
+		collection.customKey = p.customKey
+		collection.collectionOf = p
+		_, err = d.registerProviders([]registration{
+			{pType: eType, provider: p.setStrategyByValueRoundRobin(pValue, values)},
+			{pType: pType, provider: collection.setStrategyByValue(pValue)},
+		})
+		return err
 	}
 
+	_, err := d.registerProvider(pType, p.setStrategyByValue(pValue))
 	return err
 }
 
-// provideFunction adds function provider to container
+// provideFunction adds providers of all return values of function to container, if any of them can't be added none
+// of them are added
 func (d *DI) provideFunction(function any, options []ProviderOption) error {
+	if reflect.ValueOf(function).IsNil() {
+		return errors.New("can't provide nil function")
+	}
+
 	vType := reflect.TypeOf(function)
+	if vType.NumOut() == 0 {
+		return fmt.Errorf("can't add func provider %q without return values", vType.String())
+	}
+	if onlyErrors(vType) {
+		return fmt.Errorf("can't add func provider %q: constructor returns only error, nothing to provide",
+			vType.String())
+	}
+	if isCleanupConstructor(vType) {
+		return d.provideFunctionValue(function, vType.Out(0), 0, options)
+	}
 
-	provided := false
+	options = d.withDefaultOptions(options)
+	var registrations []registration
 	for i := 0; i < vType.NumOut(); i++ {
-		if err := d.provideFunctionValue(function, vType.Out(i), i, options); err != nil {
+		valueRegistrations, err := functionValueRegistrations(function, vType.Out(i), i, options)
+		if err != nil {
 			return err
 		}
-		provided = true
-	}
-	if !provided {
-		return fmt.Errorf("can't add func provider %q without return values", vType.String())
+		registrations = append(registrations, valueRegistrations...)
 	}
 
-	return nil
+	return d.provideRegistrations(registrations)
 }
 
 // provideFunctionValue adds function value provider to container
 func (d *DI) provideFunctionValue(function any, pType reflect.Type, index int, options []ProviderOption) error {
-	if ok, err := d.canAddProvider(pType); err != nil {
+	registrations, err := functionValueRegistrations(function, pType, index, d.withDefaultOptions(options))
+	if err != nil {
 		return err
-	} else if !ok {
-		return nil
+	}
+	return d.provideRegistrations(registrations)
+}
+
+// functionValueRegistrations returns providers of function value that have to be registered
+func functionValueRegistrations(
+	function any, pType reflect.Type, index int, options []ProviderOption,
+) ([]registration, error) {
+	if reflect.ValueOf(function).IsNil() {
+		return nil, errors.New("can't provide nil function")
+	}
+	if isTypeErr(pType) {
+		return nil, nil
+	}
+	if isOut(pType) {
+		return outRegistrations(function, pType, index, options)
 	}
 
-	var err error
 	p := newProviderFromOptions(options)
+	if err := p.checkWeights(pType); err != nil {
+		return nil, err
+	}
+	if fType := reflect.TypeOf(function); len(p.paramNames) > fType.NumIn() {
+		return nil, fmt.Errorf("can't use %d parameter names for function %q with %d parameters",
+			len(p.paramNames), fType.String(), fType.NumIn())
+	}
+
+	if name, ok := p.namesFor[index]; ok {
+		p.name = name
+	}
+
 	if p.useRoundRobin {
-		if eType, ok := elementType(pType); ok {
-			err = d.addProvider(eType, p.setStrategyByFunctionValueRoundRobin(function, index))
-		} else {
-			err = newErrorProviderCantRoundRobin(pType)
+		eType, err := roundRobinElementType(pType, p)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		err = d.addProvider(pType, p.setStrategyByFunctionValue(function, index))
+		p.setStrategyByFunctionValueRoundRobin(function, index)
+		return []registration{
+			{pType: eType, provider: p},
+			{pType: pType, provider: newCollectionProvider(p)},
+		}, nil
 	}
+
+	p.setStrategyByFunctionValue(function, index)
+	return []registration{{pType: pType, provider: p}}, nil
+}
+
+// provideRegistrations adds all function providers to container at once and eagerly loads added ones
+func (d *DI) provideRegistrations(registrations []registration) error {
+	added, err := d.registerProviders(registrations)
 	if err != nil {
 		return err
 	}
 
-	if p.eagerLoading {
-		if _, err = p.provide(d); err != nil {
-			return fmt.Errorf("failed to eagerly load value of type %q: %w", pType, err)
+	for i, r := range registrations {
+		if !added[i] {
+			continue
 		}
-		if p.useRoundRobin {
-			p.roundRobinIndex--
+		if err = d.loadEagerly(r.pType, r.provider); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// SetOnProvide sets hook that is called before each provider is added to container (nil to remove hook), if hook
+// returns an error provider isn't added and the error is returned, hook is inherited by child containers that don't
+// have their own hook, it isn't called for container itself provided in [NewFrom]
+func (d *DI) SetOnProvide(onProvide func(pType reflect.Type) error) {
+	_ = d.update(func(s *state) error {
+		s.onProvide = onProvide
+		return nil
+	})
+}
+
+// SetDefaultOptions sets provider's options that are applied to all providers added to container or its children
+// after this call (nil to remove them), options passed to provide methods are applied after defaults, so they can
+// override them (e.g. default [WithMultiInstance] and [WithSingleton] for specific provider), children that have their
+// own defaults don't inherit defaults of parents
+func (d *DI) SetDefaultOptions(options ...ProviderOption) {
+	_ = d.update(func(s *state) error {
+		s.defaultOptions = options
+		return nil
+	})
+}
+
+// withDefaultOptions returns default options of container or the closest parent that has them followed by options,
+// key function of [WithKeyFunc] is called here, so it's called once on provide no matter how many times options apply
+func (d *DI) withDefaultOptions(options []ProviderOption) []ProviderOption {
+	for di := d; di != nil; di = di.parent {
+		if defaults := di.load().defaultOptions; len(defaults) != 0 {
+			options = append(defaults[:len(defaults):len(defaults)], options...)
+			break
+		}
+	}
+	return withEvaluatedKey(options)
+}
+
+// withEvaluatedKey returns options followed by option that sets key returned by key function of [WithKeyFunc]
+func withEvaluatedKey(options []ProviderOption) []ProviderOption {
+	keyFunc := newProviderFromOptions(options).keyFunc
+	if keyFunc == nil {
+		return options
+	}
+
+	key := keyFunc()
+	return append(options[:len(options):len(options)], func(p *provider) {
+		p.customKey = key
+		p.keyFunc = nil
+	})
+}
+
+// getOnProvide returns provide hook of container or the closest parent that has it
+func (d *DI) getOnProvide() func(pType reflect.Type) error {
+	for di := d; di != nil; di = di.parent {
+		if onProvide := di.load().onProvide; onProvide != nil {
+			return onProvide
+		}
+	}
+	return nil
+}
+
+// EagerByDefault makes all function providers added after it eagerly loaded, unless they use [WithLazy] option
+func (d *DI) EagerByDefault() {
+	_ = d.update(func(s *state) error {
+		s.eager = true
+		return nil
+	})
+}
+
+// loadEagerly provides value of provider right away if it uses eager loading
+func (d *DI) loadEagerly(pType reflect.Type, p *provider) error {
+	if !p.eagerLoading && (p.lazy || !d.load().eager) {
+		return nil
+	}
+
+	if _, err := p.provide(d, newResolution(d, p.key(pType))); err != nil {
+		return fmt.Errorf("failed to eagerly load value of type %q: %w", pType, err)
+	}
+	if p.useRoundRobin {
+		p.roundRobinIndex--
+	}
 
 	return nil
 }
 
 // invoke calls function (or [reflect.Value] of kind [reflect.Func]) with dependencies provided from the container
-func (d *DI) invoke(function any) ([]reflect.Value, error) {
+func (d *DI) invoke(function any, res *resolution) ([]reflect.Value, error) {
+	return d.invokeWithArgs(function, res, nil, nil)
+}
+
+// invokeWithArgs is like [DI.invoke], but uses arguments for parameters that can't be resolved from the container
+// (see [WithConstructorArgs]) and resolves parameters by names (see [WithParamNames])
+func (d *DI) invokeWithArgs(
+	function any, res *resolution, args []reflect.Value, names []string,
+) ([]reflect.Value, error) {
 	var fType reflect.Type
 	vType, ok := function.(reflect.Value)
 	if ok && vType.IsValid() {
@@ -184,68 +772,215 @@ func (d *DI) invoke(function any) ([]reflect.Value, error) {
 		vType = reflect.ValueOf(function)
 	}
 
-	if fType == nil || fType.Kind() != reflect.Func {
-		return nil, fmt.Errorf("can't invoke a non-function or nil value")
+	if fType == nil || fType.Kind() != reflect.Func || vType.IsNil() {
+		return nil, newErrorNotAFunction()
 	}
 
+	var usedArgs []bool
+	if len(args) != 0 {
+		usedArgs = make([]bool, len(args))
+	}
+
+	res = res.forCall()
+	defer res.unbind()
+
 	paramValues := make([]reflect.Value, 0, fType.NumIn())
 	for i := 0; i < fType.NumIn(); i++ {
-		paramValue, err := d.invokeParam(fType.In(i), i)
+		if fType.IsVariadic() && i == fType.NumIn()-1 {
+			variadicValues, err := d.invokeVariadicParam(fType.In(i).Elem(), i, res)
+			if err != nil {
+				return nil, err
+			}
+			paramValues = append(paramValues, variadicValues...)
+			continue
+		}
+
+		key := providerKey{pType: fType.In(i), name: paramName(names, i)}
+		if len(args) != 0 && !isIn(key.pType) && !d.hasKey(key) {
+			if arg, ok := takeArg(args, usedArgs, key.pType); ok {
+				paramValues = append(paramValues, arg)
+				continue
+			}
+		}
+
+		var paramValue reflect.Value
+		var err error
+		if key.name != "" {
+			paramValue, err = d.invokeParamKey(key, i, res)
+		} else {
+			paramValue, err = d.invokeParam(key.pType, i, res)
+		}
 		if err != nil {
 			return nil, err
 		}
 		paramValues = append(paramValues, paramValue)
 	}
 
-	return functionCall(vType, paramValues)
+	results, err := functionCall(vType, paramValues, res.settings.recoverPanics)
+	if err != nil {
+		return nil, res.wrapError(err)
+	}
+	return results, nil
 }
 
-// invokeParam get one dependency from container
-func (d *DI) invokeParam(param reflect.Type, i int) (reflect.Value, error) {
-	p, ok := d.getProvider(param)
+// invokeVariadicParam get all dependencies of element type of variadic parameter from container, group members of
+// element type are used if there are any, otherwise elements of collection from provider of slice type, otherwise
+// value of provider of element type is used as the only element, if there are no such dependencies, no values returned
+func (d *DI) invokeVariadicParam(elemType reflect.Type, i int, res *resolution) ([]reflect.Value, error) {
+	key := providerKey{pType: reflect.SliceOf(elemType)}
+	p, owner, ok := d.lookupGroup(key)
 	if !ok {
-		if d.parent != nil {
-			return d.parent.invokeParam(param, i)
+		p, owner, ok = d.lookupProvider(key)
+	}
+	if ok {
+		paramValue, err := d.provideParam(key, p, owner, i, res)
+		if err != nil {
+			return nil, err
 		}
-		return reflect.Value{}, fmt.Errorf("not found provider for %d parameter of type %q",
-			i+1, param.String())
+
+		paramValues := make([]reflect.Value, 0, paramValue.Len())
+		for j := 0; j < paramValue.Len(); j++ {
+			paramValues = append(paramValues, paramValue.Index(j))
+		}
+		return paramValues, nil
+	}
+
+	if !d.Has(elemType) {
+		return nil, nil
 	}
 
-	paramValue, err := p.provide(d)
+	paramValue, err := d.invokeParam(elemType, i, res)
 	if err != nil {
-		return reflect.Value{}, fmt.Errorf("failed to provide %d parameter of type %q: %w",
-			i+1, param.String(), err)
+		return nil, err
 	}
+	return []reflect.Value{paramValue}, nil
+}
 
-	return paramValue, nil
+// paramName returns name of parameter by its index or empty string if parameter is unnamed
+func paramName(names []string, i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+	return ""
 }
 
-// newErrorProviderAlreadyExists returns an error indicating that the provider of this type already exists
-func newErrorProviderAlreadyExists(pType reflect.Type) error {
-	return fmt.Errorf("provider of type %q already exists", pType.String())
+// takeArg returns the first not yet used argument assignable to type and marks it as used
+func takeArg(args []reflect.Value, usedArgs []bool, pType reflect.Type) (reflect.Value, bool) {
+	for i, arg := range args {
+		if !usedArgs[i] && arg.IsValid() && arg.Type().AssignableTo(pType) {
+			usedArgs[i] = true
+			return arg, true
+		}
+	}
+	return reflect.Value{}, false
 }
 
-// newErrorProviderCantRoundRobin returns an error indicating that the provider of this type is not suitable for
-// round-robin
-func newErrorProviderCantRoundRobin(pType reflect.Type) error {
-	return fmt.Errorf("can't round-robin value of type %q, must be a slice or an array", pType.String())
+// invokeParam get one dependency from container
+func (d *DI) invokeParam(param reflect.Type, i int, res *resolution) (reflect.Value, error) {
+	if isIn(param) {
+		return d.invokeIn(param, i, res)
+	}
+	return d.invokeParamKey(providerKey{pType: param}, i, res)
 }
 
-// elementType returns type of element if the type is (pointer to) slice or array
+// invokeParamKey get one dependency by provider key from container, [DI] itself is resolved as container that
+// resolution was started from (not the one that owns provider), same applies to [Resolver] if there is no provider of
+// it, slices of types that have group members are assembled
+// from all members if there is no provider for slice type itself
+func (d *DI) invokeParamKey(key providerKey, i int, res *resolution) (reflect.Value, error) {
+	if key == diKey && res.scope != nil {
+		return containerValue(res, key), nil
+	}
+
+	if p, owner, ok := d.lookupProvider(key); ok {
+		return d.provideParam(key, p, owner, i, res)
+	}
+	if isContainerKey(key) && res.scope != nil {
+		return containerValue(res, key), nil
+	}
+	if key == contextKey && res.ctx != nil {
+		return contextValue(res.ctx), nil
+	}
+	if p, owner, ok := d.lookupGroup(key); ok {
+		return d.provideParam(key, p, owner, i, res)
+	}
+
+	matchKey, p, owner, err := d.lookupAssignable(i, key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if p != nil {
+		return d.provideParam(matchKey, p, owner, i, res)
+	}
+
+	return reflect.Value{}, res.wrapError(newErrorProviderNotFound(i, key))
+}
+
+// provideParam get one dependency using provider that is owned by container
+func (d *DI) provideParam(key providerKey, p *provider, owner *DI, i int, res *resolution) (reflect.Value, error) {
+	res, err := res.push(key)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	observer := res.settings.observer
+	if observer != nil {
+		observer.OnResolveStart(key.pType)
+	}
+
+	start := time.Now()
+	paramValue, err := p.provide(owner, res)
+
+	if observer != nil {
+		observer.OnResolveEnd(key.pType, err, time.Since(start))
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("failed to provide %d parameter of %s: %w", i+1, key.describe(), err)
+	}
+
+	return paramValue, nil
+}
+
+// elementType returns type of element if the type is (pointer to) slice, array or map
 func elementType(vType reflect.Type) (reflect.Type, bool) {
 	checkType := vType
 	if checkType.Kind() == reflect.Ptr {
 		checkType = checkType.Elem()
 	}
-	if checkType.Kind() == reflect.Slice || checkType.Kind() == reflect.Array {
+	if checkType.Kind() == reflect.Slice || checkType.Kind() == reflect.Array || checkType.Kind() == reflect.Map {
 		checkType = checkType.Elem()
 		return checkType, true
 	}
 	return checkType, false
 }
 
-// functionCall call a user's function
-func functionCall(fValue reflect.Value, params []reflect.Value) ([]reflect.Value, error) {
+// roundRobinElementType returns type of elements of round-robin provider, elements of fan out providers must be
+// channels
+func roundRobinElementType(pType reflect.Type, p *provider) (reflect.Type, error) {
+	eType, ok := elementType(pType)
+	if !ok {
+		return nil, newErrorProviderCantRoundRobin(pType)
+	}
+	if p.fanOut && eType.Kind() != reflect.Chan {
+		return nil, &wrappedError{
+			message: fmt.Sprintf("can't fan out value of type %q, elements must be channels", pType.String()),
+			err:     ErrCantRoundRobin,
+		}
+	}
+	return eType, nil
+}
+
+// functionCall call a user's function, if recoverPanics is set panic of function is returned as an error
+func functionCall(fValue reflect.Value, params []reflect.Value, recoverPanics bool) (_ []reflect.Value, err error) {
+	if recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newErrorPanicked(fValue.Type(), r)
+			}
+		}()
+	}
+
 	results := fValue.Call(params)
 	for _, result := range results {
 		if isTypeErr(result.Type()) {
@@ -257,7 +992,25 @@ func functionCall(fValue reflect.Value, params []reflect.Value) ([]reflect.Value
 	return results, nil
 }
 
-// isTypeErr checks if the type is built-in error
+// typeOf returns type of type parameter, works for interface types as well
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// onlyErrors reports whether all return values of function type are errors
+func onlyErrors(fType reflect.Type) bool {
+	for i := 0; i < fType.NumOut(); i++ {
+		if !isTypeErr(fType.Out(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// errorType represents built-in error type
+var errorType = typeOf[error]()
+
+// isTypeErr checks if the type is built-in error or interface that embeds it (e.g. custom error interface)
 func isTypeErr(vType reflect.Type) bool {
-	return vType.String() == "error"
+	return vType == errorType || vType.Kind() == reflect.Interface && vType.Implements(errorType)
 }
@@ -1,13 +1,19 @@
 package mdi
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 var errTest = errors.New("test_err")
@@ -59,6 +65,24 @@ func TestDI_ProvideAndInvoke(t *testing.T) {
 				}
 			},
 		},
+		"success_value_map_round_robin": {
+			provide:         map[string]int{"c": 3, "a": 1, "b": 2},
+			providerOptions: []ProviderOption{WithRoundRobin()},
+			invoke: func(i1, i2, i3, i4 int) {
+				if i1 != 1 || i2 != 2 || i3 != 3 || i4 != 1 {
+					t.Fatalf("unexpected: %d, %d, %d, %d", i1, i2, i3, i4)
+				}
+			},
+		},
+		"success_func_map_round_robin": {
+			provide:         func() map[int]string { return map[int]string{3: "c", 1: "a", 2: "b"} },
+			providerOptions: []ProviderOption{WithRoundRobin()},
+			invoke: func(s1, s2, s3, s4 string) {
+				if s1 != "a" || s2 != "b" || s3 != "c" || s4 != "a" {
+					t.Fatalf("unexpected: %s, %s, %s, %s", s1, s2, s3, s4)
+				}
+			},
+		},
 		"success_func_int_round_robin": {
 			provide:         func() []int { return []int{1, 2} },
 			providerOptions: []ProviderOption{WithRoundRobin()},
@@ -101,6 +125,33 @@ func TestDI_ProvideAndInvoke(t *testing.T) {
 				}
 			},
 		},
+		"success_func_transient": {
+			provide:         func() int { return rand.Int() },
+			providerOptions: []ProviderOption{WithTransient()},
+			invoke: func(i1, i2 int) {
+				if i1 == i2 {
+					t.Fatalf("expected diff: %d %d", i1, i2)
+				}
+			},
+		},
+		"success_func_singleton": {
+			provide:         func() int { return rand.Int() },
+			providerOptions: []ProviderOption{WithTransient(), WithSingleton()},
+			invoke: func(i1, i2 int) {
+				if i1 != i2 {
+					t.Fatalf("expected euality: %d %d", i1, i2)
+				}
+			},
+		},
+		"success_func_cache_disabled": {
+			provide:         func() int { return rand.Int() },
+			providerOptions: []ProviderOption{WithCache(false)},
+			invoke: func(i1, i2 int) {
+				if i1 == i2 {
+					t.Fatalf("expected diff: %d %d", i1, i2)
+				}
+			},
+		},
 		"success_value_provide_interface": {
 			provide: io.Reader(os.Stdin),
 			invoke:  func(r *os.File) {},
@@ -109,6 +160,47 @@ func TestDI_ProvideAndInvoke(t *testing.T) {
 			provide: func() io.Reader { return os.Stdin },
 			invoke:  func(r io.Reader) {},
 		},
+		"success_invoke_variadic": {
+			provide: 1,
+			invoke: func(s ...int) {
+				if len(s) != 1 || s[0] != 1 {
+					t.Fatalf("unexpected: %v", s)
+				}
+			},
+		},
+		"success_invoke_variadic_slice": {
+			provide: []string{"a", "b"},
+			invoke: func(s ...string) {
+				if len(s) != 2 || s[0] != "a" || s[1] != "b" {
+					t.Fatalf("unexpected: %v", s)
+				}
+			},
+		},
+		"success_invoke_variadic_round_robin": {
+			provide:         []string{"a", "b"},
+			providerOptions: []ProviderOption{WithRoundRobin()},
+			invoke: func(s ...string) {
+				if len(s) != 2 || s[0] != "a" || s[1] != "b" {
+					t.Fatalf("unexpected: %v", s)
+				}
+			},
+		},
+		"success_invoke_variadic_empty": {
+			provide: 1,
+			invoke: func(i int, s ...string) {
+				if len(s) != 0 {
+					t.Fatalf("unexpected: %v", s)
+				}
+			},
+		},
+		"success_func_variadic": {
+			provide: func(s ...string) int { return len(s) },
+			invoke: func(i int) {
+				if i != 0 {
+					t.Fatalf("unexpected: %d", i)
+				}
+			},
+		},
 		"error_value_not_found": {
 			provide:   1,
 			invoke:    func(s string) {},
@@ -142,6 +234,17 @@ func TestDI_ProvideAndInvoke(t *testing.T) {
 			provideErr:      errors.New("can't round-robin"),
 			providerOptions: []ProviderOption{WithRoundRobin()},
 		},
+		"error_value_empty_round_robin": {
+			provide:         []int{},
+			provideErr:      ErrCantRoundRobin,
+			providerOptions: []ProviderOption{WithRoundRobin()},
+		},
+		"error_func_empty_round_robin": {
+			provide:         func() []int { return nil },
+			providerOptions: []ProviderOption{WithRoundRobin()},
+			invoke:          func(i int) { t.Fatalf("should not be called") },
+			invokeErr:       ErrCantRoundRobin,
+		},
 		"error_func_provide": {
 			provide:   func() (int, error) { return 0, errTest },
 			invoke:    func(i int) { t.Fatalf("should not be called") },
@@ -197,3 +300,1700 @@ func TestDI_ProvideAndInvoke(t *testing.T) {
 		})
 	}
 }
+
+func TestDI_ZeroValue(t *testing.T) {
+	di := &DI{}
+
+	if di.Has(typeOf[int]()) || di.Reset(typeOf[int]()) {
+		t.Fatal("unexpected provider")
+	}
+	if _, err := Resolve[int](di); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+	if err := di.Validate(); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if snapshot := di.Snapshot(); len(snapshot) != 0 {
+		t.Fatalf("unexpected: %v", snapshot)
+	}
+	if plugins := MustResolveGroup[testPlugin](di); len(plugins) != 0 {
+		t.Fatalf("unexpected: %d", len(plugins))
+	}
+
+	di.MustProvide(1).MustProvide(func(i int) string { return strconv.Itoa(i) })
+	if err := ProvideValue[testPlugin](di, testNamedPlugin("a"), WithGroup()); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	err := di.Invoke(func(s string, d *DI, plugins []testPlugin) {
+		if s != "1" || d != di || len(plugins) != 1 {
+			t.Fatalf("unexpected: %q, %v, %d", s, d, len(plugins))
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	if value := MustResolve[string](di.Clone()); value != "1" {
+		t.Fatalf("unexpected: %q", value)
+	}
+	if value := MustResolve[int](di.Scope()); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	if err = di.Close(); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+}
+
+func TestDI_ScopeAndParent(t *testing.T) {
+	root := New().MustProvide(1)
+	scope := root.Scope().Scope().Scope()
+
+	depth := 0
+	for di := scope; di != root; di = di.Parent() {
+		if di == nil {
+			t.Fatalf("expected to reach root container")
+		}
+		depth++
+	}
+	if depth != 3 {
+		t.Fatalf("unexpected: %d", depth)
+	}
+	if root.Parent() != nil {
+		t.Fatalf("unexpected parent of root container")
+	}
+
+	scope.MustInvoke(func(i int, di *DI) {
+		if i != 1 || di != scope {
+			t.Fatalf("unexpected: %d, %p", i, di)
+		}
+	})
+}
+
+func TestDI_Shadow(t *testing.T) {
+	parent := New().MustProvide(1).MustProvide(func(i int) string { return strconv.Itoa(i) })
+	child := parent.Scope()
+	sibling := parent.Scope()
+
+	if err := child.Shadow(2); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	testCases := []struct {
+		name     string
+		di       *DI
+		expected int
+	}{
+		{name: "child", di: child, expected: 2},
+		{name: "grandchild", di: child.Scope(), expected: 2},
+		{name: "sibling", di: sibling, expected: 1},
+		{name: "parent", di: parent, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if value := MustResolve[int](tc.di); value != tc.expected {
+				t.Fatalf("unexpected: %d", value)
+			}
+		})
+	}
+
+	if value := MustResolve[string](child); value != "1" {
+		t.Fatalf("unexpected: %q", value)
+	}
+
+	if err := New().Shadow(1); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDI_WithValue(t *testing.T) {
+	type request struct {
+		id string
+	}
+
+	di := New().MustProvide(func(di *DI) (string, error) {
+		r, err := Resolve[*request](di)
+		if err != nil {
+			return "", err
+		}
+		return "handled " + r.id, nil
+	}, WithMultiInstance())
+
+	for _, id := range []string{"1", "2"} {
+		di.MustWithValue(&request{id: id}).MustInvoke(func(r *request, s string) {
+			if r.id != id || s != "handled "+id {
+				t.Fatalf("unexpected: %q, %q", r.id, s)
+			}
+		})
+	}
+
+	if HasType[*request](di) {
+		t.Fatalf("unexpected request provider in parent")
+	}
+	if err := di.Invoke(func(s string) {}); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+
+	t.Run("function", func(t *testing.T) {
+		scope, err := di.WithValue(func() int { return 1 })
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if HasType[int](scope) {
+			t.Fatal("unexpected constructor provider")
+		}
+		if fn := MustResolve[func() int](scope); fn() != 1 {
+			t.Fatalf("unexpected: %d", fn())
+		}
+
+		if _, err = di.WithValue(func() {}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("default_options", func(t *testing.T) {
+		for _, option := range []ProviderOption{WithGroup(), WithName("a"), WithKey("k")} {
+			parent := New()
+			parent.SetDefaultOptions(option)
+
+			scope, err := parent.WithValue(&request{id: "1"})
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if r := MustResolve[*request](scope); r.id != "1" {
+				t.Fatalf("unexpected: %q", r.id)
+			}
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		if _, err := di.WithValue(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDI_Has(t *testing.T) {
+	parent := New().MustProvide(1)
+	child := NewFrom(parent).MustProvide("test")
+
+	if !child.Has(reflect.TypeOf(1)) || !HasType[int](child) {
+		t.Fatalf("expected int to be found in parent")
+	}
+	if !child.Has(reflect.TypeOf("")) || !HasType[string](child) {
+		t.Fatalf("expected string to be found in child")
+	}
+	if HasType[string](parent) {
+		t.Fatalf("unexpected string in parent")
+	}
+	if child.Has(reflect.TypeOf(1.0)) || HasType[float64](child) {
+		t.Fatalf("unexpected float64")
+	}
+	if !HasType[*DI](child) || !HasType[Resolver](child) || !HasType[Provider](child) {
+		t.Fatalf("expected container itself to be found")
+	}
+}
+
+func TestDI_ProvideWithPriority(t *testing.T) {
+	t.Run("higher_last", func(t *testing.T) {
+		di := New().
+			MustProvide(1, WithPriority(1)).
+			MustProvide(func() int { return 10 }, WithPriority(10))
+
+		di.MustInvoke(func(i int) {
+			if i != 10 {
+				t.Fatalf("unexpected: %d", i)
+			}
+		})
+	})
+
+	t.Run("higher_first", func(t *testing.T) {
+		di := New().
+			MustProvide(func() int { return 10 }, WithPriority(10)).
+			MustProvide(func() (int, error) { return 1, errTest }, WithPriority(1), WithEagerLoading())
+
+		di.MustInvoke(func(i int) {
+			if i != 10 {
+				t.Fatalf("unexpected: %d", i)
+			}
+		})
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		err := New().MustProvide(1, WithPriority(1)).Provide(2, WithPriority(1))
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+	})
+}
+
+func TestDI_ProvideAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideAll(1, func(i int) string { return strconv.Itoa(i) }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[string](di); value != "1" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		di := New()
+		err := di.ProvideAll(1, "a", func() int { return 2 }, 2.5)
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if !strings.Contains(err.Error(), "provide 2 (func() int)") {
+			t.Fatalf("expected index and type in error, but got: %v", err)
+		}
+		if !HasType[string](di) || HasType[float64](di) {
+			t.Fatal("unexpected providers")
+		}
+	})
+}
+
+func TestDI_ProvideValues(t *testing.T) {
+	type config struct {
+		Name string
+	}
+
+	t.Run("success", func(t *testing.T) {
+		di := New()
+		format := func(i int) string { return strconv.Itoa(i) }
+		if err := di.ProvideValues(1, "a", config{Name: "b"}, format); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		err := di.Invoke(func(i int, s string, c config, f func(int) string) {
+			if i != 1 || s != "a" || c.Name != "b" || f(2) != "2" {
+				t.Fatalf("unexpected: %d, %q, %+v", i, s, c)
+			}
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := New().ProvideValues(1, "a", 2)
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if !strings.Contains(err.Error(), "provide value 2 (int)") {
+			t.Fatalf("expected index and type in error, but got: %v", err)
+		}
+
+		if err = New().ProvideValues(1, nil); err == nil || !strings.Contains(err.Error(), "provide value 1") {
+			t.Fatalf("expected index in error, but got: %v", err)
+		}
+	})
+}
+
+func TestDI_ProvideFuncValue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideFuncValue(func(i int) string { return strconv.Itoa(i * 2) }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		di.MustProvide(func(format func(int) string) string { return format(21) })
+
+		if value := MustResolve[string](di); value != "42" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("error_not_a_function", func(t *testing.T) {
+		if err := New().ProvideFuncValue(1); !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+		if err := New().ProvideFuncValue((func())(nil)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDI_ProvideWithConditional(t *testing.T) {
+	var enabled atomic.Bool
+	parent := New().MustProvide(1)
+	di := parent.Scope().MustProvide(2, WithConditional(enabled.Load))
+	standalone := New().MustProvide(2, WithConditional(enabled.Load))
+
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	if _, err := Resolve[int](standalone); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+
+	enabled.Store(true)
+	if value := MustResolve[int](di); value != 2 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	if value := MustResolve[int](standalone); value != 2 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	enabled.Store(false)
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+}
+
+func TestDI_ProvideWithCopy(t *testing.T) {
+	type config struct {
+		Name  string
+		Ports []int
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		di := New().MustProvide(config{Name: "a"}, WithCopy())
+
+		di.MustInvoke(func(c config) {
+			c.Name = "b"
+		})
+		if value := MustResolve[config](di); value.Name != "a" {
+			t.Fatalf("unexpected: %q", value.Name)
+		}
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		original := &config{Name: "a"}
+		di := New().MustProvide(original, WithCopy())
+
+		first := MustResolve[*config](di)
+		first.Name = "b"
+		if original.Name != "a" || first == original {
+			t.Fatalf("unexpected: %q", original.Name)
+		}
+		if value := MustResolve[*config](di); value.Name != "a" {
+			t.Fatalf("unexpected: %q", value.Name)
+		}
+	})
+
+	t.Run("slice_and_map", func(t *testing.T) {
+		di := New().MustProvide([]int{1, 2}, WithCopy()).MustProvide(map[string]int{"a": 1}, WithCopy())
+
+		MustResolve[[]int](di)[0] = 3
+		MustResolve[map[string]int](di)["a"] = 2
+		if value := MustResolve[[]int](di); value[0] != 1 {
+			t.Fatalf("unexpected: %d", value[0])
+		}
+		if value := MustResolve[map[string]int](di); value["a"] != 1 {
+			t.Fatalf("unexpected: %d", value["a"])
+		}
+	})
+
+	t.Run("without_copy", func(t *testing.T) {
+		di := New().MustProvide([]int{1, 2})
+
+		MustResolve[[]int](di)[0] = 3
+		if value := MustResolve[[]int](di); value[0] != 3 {
+			t.Fatalf("unexpected: %d", value[0])
+		}
+	})
+
+	t.Run("error_not_copyable", func(t *testing.T) {
+		if err := New().Provide(make(chan int), WithCopy()); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := ProvideValue[io.Reader](New(), os.Stdin, WithCopy()); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDI_SetDefaultOptions(t *testing.T) {
+	di := New()
+	di.SetDefaultOptions(WithMultiInstance())
+
+	calls := 0
+	di.MustProvide(func() int { calls++; return calls })
+	di.MustProvide(func() string { calls++; return strconv.Itoa(calls) }, WithSingleton())
+
+	if MustResolve[int](di) == MustResolve[int](di) {
+		t.Fatal("expected new instances")
+	}
+	if MustResolve[string](di) != MustResolve[string](di) {
+		t.Fatal("expected cached instance")
+	}
+
+	scope := di.Scope()
+	if MustResolve[*DI](scope) != scope {
+		t.Fatal("unexpected container")
+	}
+	scope.MustProvide(func() float64 { calls++; return float64(calls) })
+	if MustResolve[float64](scope) == MustResolve[float64](scope) {
+		t.Fatal("expected new instances in child")
+	}
+
+	di.SetDefaultOptions()
+	di.MustProvide(func() bool { calls++; return calls%2 == 0 })
+	if MustResolve[bool](di) != MustResolve[bool](di) {
+		t.Fatal("expected cached instance after defaults removed")
+	}
+}
+
+func TestDI_ProvideDefault(t *testing.T) {
+	t.Run("used", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideDefault(time.Second); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[time.Duration](di); value != time.Second {
+			t.Fatalf("unexpected: %s", value)
+		}
+	})
+
+	t.Run("shadowed", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideDefault(time.Second); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if err := di.Provide(time.Minute); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[time.Duration](di); value != time.Minute {
+			t.Fatalf("unexpected: %s", value)
+		}
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		di := New().MustProvide(time.Minute)
+		if err := di.ProvideDefault(time.Second); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if err := di.ProvideDefault(time.Hour); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[time.Duration](di); value != time.Minute {
+			t.Fatalf("unexpected: %s", value)
+		}
+	})
+
+	t.Run("parent", func(t *testing.T) {
+		parent := New().MustProvide(time.Minute)
+		child := parent.Scope()
+		if err := child.ProvideDefault(time.Second); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[time.Duration](child); value != time.Minute {
+			t.Fatalf("unexpected: %s", value)
+		}
+
+		if err := child.ProvideDefault(1); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		parent.MustProvide(2)
+		if value := MustResolve[int](child); value != 2 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	})
+
+	t.Run("parent_default", func(t *testing.T) {
+		parent := New()
+		if err := parent.ProvideDefault(time.Minute); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		child := parent.Scope()
+		if err := child.ProvideDefault(time.Second); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[time.Duration](child); value != time.Second {
+			t.Fatalf("unexpected: %s", value)
+		}
+	})
+}
+
+func TestDI_ProvideOnce(t *testing.T) {
+	di := New(WithAllowOverride())
+
+	added, err := di.ProvideOnce(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if !added {
+		t.Fatal("expected provider to be added")
+	}
+
+	added, err = di.ProvideOnce(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if added {
+		t.Fatal("expected provider to be ignored")
+	}
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	if _, err = di.ProvideOnce(nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDI_ConcurrentConstruction(t *testing.T) {
+	var calls atomic.Int32
+	di := New().MustProvide(func() int {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 10)
+		return 1
+	})
+
+	const goroutines = 16
+	wg := sync.WaitGroup{}
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			if err := di.Invoke(func(i int) {}); err != nil {
+				t.Errorf("unexpected error: %q", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected one call, but got: %d", calls.Load())
+	}
+}
+
+func TestDI_ConstructionCycleThroughDI(t *testing.T) {
+	di := New().
+		MustProvide(func(d *DI) (int, error) {
+			s, err := Resolve[string](d)
+			return len(s), err
+		}).
+		MustProvide(func(d *DI) (string, error) {
+			i, err := Resolve[int](d)
+			return strconv.Itoa(i), err
+		})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Resolve[int](di)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolution is deadlocked")
+	}
+
+	if _, err := Resolve[string](di); !errors.Is(err, ErrCycleDetected) {
+		t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		var calls atomic.Int32
+		di := New().MustProvide(func(d *DI) int {
+			calls.Add(1)
+			time.Sleep(time.Millisecond * 10)
+			return 1
+		})
+
+		const goroutines = 8
+		wg := sync.WaitGroup{}
+		wg.Add(goroutines)
+		for i := 0; i < goroutines; i++ {
+			go func() {
+				defer wg.Done()
+				if _, err := Resolve[int](di); err != nil {
+					t.Errorf("unexpected error: %q", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Fatalf("expected one call, but got: %d", calls.Load())
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		di := New().MustProvide(func(d *DI) (int, error) {
+			_, err := Resolve[int](d)
+			return 0, err
+		}, WithTimeout(time.Second))
+
+		start := time.Now()
+		if _, err := Resolve[int](di); !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+		if elapsed := time.Since(start); elapsed >= time.Second {
+			t.Fatalf("unexpected: %s", elapsed)
+		}
+	})
+
+	t.Run("after_return", func(t *testing.T) {
+		var injected *DI
+		di := New().
+			MustProvide(func(d *DI) string {
+				injected = d
+				return "a"
+			}).
+			MustProvide(func(s string) int { return len(s) })
+
+		if _, err := Resolve[int](di); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if s, err := Resolve[string](injected); err != nil || s != "a" {
+			t.Fatalf("unexpected: %q, %v", s, err)
+		}
+		if err := injected.Provide(1.5); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if _, err := Resolve[float64](di); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+}
+
+func TestDI_ProvideWithRoundRobinCollection(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		di := New().MustProvide([]int{1, 2, 3}, WithRoundRobin())
+
+		di.MustInvoke(func(a, b int, all []int) {
+			if a != 1 || b != 2 {
+				t.Fatalf("unexpected: %d %d", a, b)
+			}
+			if !reflect.DeepEqual(all, []int{1, 2, 3}) {
+				t.Fatalf("unexpected: %v", all)
+			}
+		})
+	})
+
+	t.Run("func", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() []int {
+			calls++
+			return []int{1, 2, 3}
+		}, WithRoundRobin())
+
+		di.MustInvoke(func(all []int, a, b int) {
+			if a != 1 || b != 2 {
+				t.Fatalf("unexpected: %d %d", a, b)
+			}
+			if !reflect.DeepEqual(all, []int{1, 2, 3}) {
+				t.Fatalf("unexpected: %v", all)
+			}
+		})
+		if calls != 1 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+
+		if all := MustResolve[[]int](di.Clone()); !reflect.DeepEqual(all, []int{1, 2, 3}) {
+			t.Fatalf("unexpected: %v", all)
+		}
+		if calls != 2 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+	})
+
+	t.Run("existing_collection", func(t *testing.T) {
+		di := New().MustProvide([]int{4}).MustProvide([]int{1, 2, 3}, WithRoundRobin())
+
+		if all := MustResolve[[]int](di); !reflect.DeepEqual(all, []int{4}) {
+			t.Fatalf("unexpected: %v", all)
+		}
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	})
+}
+
+func TestDI_ProvideWithMapKeyOrder(t *testing.T) {
+	values := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	t.Run("value", func(t *testing.T) {
+		di := New().MustProvide(values, WithRoundRobin(), WithMapKeyOrder([]string{"c", "a", "b"}))
+
+		var rotation []int
+		for i := 0; i < 4; i++ {
+			rotation = append(rotation, MustResolve[int](di))
+		}
+		if expected := []int{3, 1, 2, 3}; !reflect.DeepEqual(rotation, expected) {
+			t.Fatalf("expected rotation: %v, but got: %v", expected, rotation)
+		}
+	})
+
+	t.Run("func", func(t *testing.T) {
+		di := New().MustProvide(func() map[string]int { return values },
+			WithRoundRobin(), WithMapKeyOrder([]string{"b", "c", "a"}))
+
+		var rotation []int
+		for i := 0; i < 3; i++ {
+			rotation = append(rotation, MustResolve[int](di))
+		}
+		if expected := []int{2, 3, 1}; !reflect.DeepEqual(rotation, expected) {
+			t.Fatalf("expected rotation: %v, but got: %v", expected, rotation)
+		}
+	})
+
+	t.Run("error_mismatch", func(t *testing.T) {
+		orders := map[string]ProviderOption{
+			"missing_key":   WithMapKeyOrder([]string{"a", "b"}),
+			"unknown_key":   WithMapKeyOrder([]string{"a", "b", "d"}),
+			"duplicate_key": WithMapKeyOrder([]string{"a", "b", "b"}),
+			"key_type":      WithMapKeyOrder([]int{1, 2, 3}),
+		}
+		for name, order := range orders {
+			if err := New().Provide(values, WithRoundRobin(), order); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		}
+
+		if err := New().Provide([]int{1}, WithRoundRobin(), WithMapKeyOrder([]int{0})); err == nil {
+			t.Fatal("expected error for slice")
+		}
+
+		di := New().MustProvide(func() map[string]int { return values },
+			WithRoundRobin(), WithMapKeyOrder([]string{"a"}))
+		if _, err := Resolve[int](di); err == nil {
+			t.Fatal("expected error for func")
+		}
+	})
+}
+
+func TestDI_ProvideWithRoundRobinLengthChange(t *testing.T) {
+	lengths := []int{3, 1, 2}
+	call := 0
+	di := New().MustProvide(func() []int {
+		values := make([]int, lengths[call%len(lengths)])
+		for i := range values {
+			values[i] = i
+		}
+		call++
+		return values
+	}, WithRoundRobin(), WithMultiInstance())
+
+	// Index moves forward on each call and wraps to the first element once it reaches current length
+	di.MustInvoke(func(i1, i2, i3, i4, i5, i6 int) {
+		got := []int{i1, i2, i3, i4, i5, i6}
+		expected := []int{0, 0, 1, 2, 0, 1}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected values: %v, but got: %v", expected, got)
+		}
+	})
+}
+
+func TestDI_ProvideWithFanOut(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		channels := []chan int{make(chan int, 1), make(chan int, 1), make(chan int, 1)}
+		di := New().MustProvide(channels, WithFanOut())
+
+		for i := 0; i < 6; i++ {
+			if ch := MustResolve[chan int](di); ch != channels[i%3] {
+				t.Fatalf("unexpected channel at %d", i)
+			}
+		}
+	})
+
+	t.Run("error_not_channels", func(t *testing.T) {
+		err := New().Provide([]int{1, 2}, WithFanOut())
+		if !errors.Is(err, ErrCantRoundRobin) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCantRoundRobin, err)
+		}
+
+		err = New().Provide(func() []int { return nil }, WithFanOut())
+		if !errors.Is(err, ErrCantRoundRobin) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCantRoundRobin, err)
+		}
+	})
+}
+
+func TestDI_ProvideWithWeights(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		for _, provide := range []any{[]string{"a", "b"}, func() []string { return []string{"a", "b"} }} {
+			di := New().MustProvide(provide, WithRoundRobin(), WithWeights([]int{2, 1}))
+
+			counts := map[string]int{}
+			for i := 0; i < 300; i++ {
+				counts[MustResolve[string](di)]++
+			}
+			if counts["a"] != 200 || counts["b"] != 100 {
+				t.Fatalf("unexpected: %v", counts)
+			}
+		}
+	})
+
+	t.Run("error_length", func(t *testing.T) {
+		err := New().Provide([]string{"a", "b"}, WithRoundRobin(), WithWeights([]int{1}))
+		if !errors.Is(err, ErrCantRoundRobin) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCantRoundRobin, err)
+		}
+
+		di := New().MustProvide(func() []string { return []string{"a"} }, WithRoundRobin(), WithWeights([]int{1, 2}))
+		if _, err = Resolve[string](di); !errors.Is(err, ErrCantRoundRobin) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCantRoundRobin, err)
+		}
+	})
+
+	t.Run("error_invalid", func(t *testing.T) {
+		if err := New().Provide([]string{"a"}, WithWeights([]int{1})); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := New().Provide([]string{"a", "b"}, WithRoundRobin(), WithWeights([]int{1, -1})); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := New().Provide([]string{"a"}, WithRoundRobin(), WithWeights([]int{0})); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
+
+func TestDI_ProvideWithStaticType(t *testing.T) {
+	t.Run("dynamic", func(t *testing.T) {
+		var r io.Reader = os.Stdin
+		di := New().MustProvide(r)
+
+		if HasType[io.Reader](di) || !HasType[*os.File](di) {
+			t.Fatalf("expected dynamic type provider")
+		}
+	})
+
+	t.Run("static", func(t *testing.T) {
+		var r io.Reader = os.Stdin
+		di := New().MustProvide(&r, WithStaticType())
+
+		if !HasType[io.Reader](di) || HasType[*os.File](di) || HasType[*io.Reader](di) {
+			t.Fatalf("expected static type provider")
+		}
+		di.MustInvoke(func(r io.Reader) {
+			if r != os.Stdin {
+				t.Fatalf("unexpected: %v", r)
+			}
+		})
+	})
+
+	t.Run("error_not_pointer", func(t *testing.T) {
+		if err := New().Provide(1, WithStaticType()); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := New().Provide((*io.Reader)(nil), WithStaticType()); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
+
+func TestDI_ProvideMultipleReturnValues(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New().MustProvide(func() (int, string, error) { return 1, "test", nil })
+
+		di.MustInvoke(func(i int, s string) {
+			if i != 1 || s != "test" {
+				t.Fatalf("unexpected: %d, %q", i, s)
+			}
+		})
+	})
+
+	t.Run("error_no_partial_registration", func(t *testing.T) {
+		di := New().MustProvide("existing")
+
+		err := di.Provide(func() (int, string) { return 1, "test" })
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if HasType[int](di) {
+			t.Fatalf("unexpected provider of the first return value")
+		}
+	})
+}
+
+func TestDI_ProvideWithNameFor(t *testing.T) {
+	di := New().MustProvide(func() (*bytes.Reader, *bytes.Buffer, error) {
+		return bytes.NewReader([]byte("reader")), bytes.NewBufferString("writer"), nil
+	}, WithNameFor(0, "in"), WithNameFor(1, "out"))
+
+	type params struct {
+		In
+		Reader *bytes.Reader `name:"in"`
+		Buffer *bytes.Buffer `name:"out"`
+	}
+	di.MustInvoke(func(p params) {
+		if p.Reader.Len() != len("reader") || p.Buffer.String() != "writer" {
+			t.Fatalf("unexpected: %+v", p)
+		}
+	})
+
+	if HasType[*bytes.Reader](di) || HasType[*bytes.Buffer](di) {
+		t.Fatalf("unexpected unnamed providers")
+	}
+}
+
+func TestDI_ProvideWithParamNames(t *testing.T) {
+	type db struct {
+		name string
+	}
+	type repo struct {
+		primary, replica *db
+	}
+
+	t.Run("success", func(t *testing.T) {
+		di := New().
+			MustProvide(&db{name: "primary"}, WithName("primary")).
+			MustProvide(&db{name: "replica"}, WithName("replica")).
+			MustProvide(func(primary, replica *db) *repo {
+				return &repo{primary: primary, replica: replica}
+			}, WithParamNames("primary", "replica"))
+
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		r := MustResolve[*repo](di)
+		if r.primary.name != "primary" || r.replica.name != "replica" {
+			t.Fatalf("unexpected: %q, %q", r.primary.name, r.replica.name)
+		}
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		di := New().
+			MustProvide(1).
+			MustProvide(2, WithName("b")).
+			MustProvide(func(a, b int) string { return strconv.Itoa(a) + strconv.Itoa(b) }, WithParamNames("", "b"))
+
+		if value := MustResolve[string](di); value != "12" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		di := New().MustProvide(1).MustProvide(func(a int) string { return "" }, WithParamNames("a"))
+
+		if err := di.Validate(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if _, err := Resolve[string](di); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_too_many_names", func(t *testing.T) {
+		if err := New().Provide(func(a int) string { return "" }, WithParamNames("a", "b")); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDI_ProvideWithTimeout(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New().MustProvide(func() int { return 1 }, WithTimeout(time.Second))
+		di.MustInvoke(func(i int) {
+			if i != 1 {
+				t.Fatalf("unexpected: %d", i)
+			}
+		})
+	})
+
+	t.Run("error_timeout", func(t *testing.T) {
+		di := New().MustProvide(func() int {
+			time.Sleep(time.Millisecond * 100)
+			return 1
+		}, WithTimeout(time.Millisecond*10))
+
+		err := di.Invoke(func(i int) { t.Fatalf("should not be called") })
+		if !errors.Is(err, ErrTimeout) {
+			t.Fatalf("expected error: %q, but got: %v", ErrTimeout, err)
+		}
+	})
+}
+
+func TestDI_ProvideWithRetry(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var calls atomic.Int32
+		di := New().MustProvide(func() (int, error) {
+			if calls.Add(1) < 3 {
+				return 0, errTest
+			}
+			return 1, nil
+		}, WithRetry(3, time.Millisecond))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if value, err := Resolve[int](di); err != nil || value != 1 {
+					t.Errorf("unexpected: %d, %v", value, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if calls.Load() != 3 {
+			t.Fatalf("unexpected: %d", calls.Load())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() (int, error) {
+			calls++
+			return 0, errTest
+		}, WithRetry(2, 0))
+
+		if _, err := Resolve[int](di); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+		if calls != 2 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+	})
+}
+
+func TestDI_Clone(t *testing.T) {
+	calls := 0
+	di := New().
+		MustProvide("test").
+		MustProvide(func() int { calls++; return calls })
+	di.MustInvoke(func(i int) {})
+
+	clone := di.Clone()
+	clone.MustProvide(1.0)
+	clone.MustProvide("override", WithPriority(1))
+
+	clone.MustInvoke(func(i int, s string, f float64, c *DI) {
+		if i != 2 {
+			t.Fatalf("expected new value, but got: %d", i)
+		}
+		if s != "override" {
+			t.Fatalf("unexpected: %q", s)
+		}
+		if c != clone {
+			t.Fatalf("expected clone container")
+		}
+	})
+
+	di.MustInvoke(func(i int, s string, c *DI) {
+		if i != 1 {
+			t.Fatalf("expected cached value, but got: %d", i)
+		}
+		if s != "test" {
+			t.Fatalf("unexpected: %q", s)
+		}
+		if c != di {
+			t.Fatalf("expected original container")
+		}
+	})
+	if HasType[float64](di) {
+		t.Fatalf("unexpected float64 in original container")
+	}
+}
+
+func TestDI_InvokeFresh(t *testing.T) {
+	calls := 0
+	di := New().
+		MustProvide(func() int {
+			calls++
+			return calls
+		}).
+		MustProvide(func(i int) string { return strconv.Itoa(i) })
+
+	if value := MustResolve[string](di); value != "1" {
+		t.Fatalf("unexpected: %q", value)
+	}
+
+	err := di.InvokeFresh(func(s string, i int) {
+		if s != "2" || i != 2 {
+			t.Fatalf("unexpected: %q, %d", s, i)
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	if value := MustResolve[string](di); value != "1" {
+		t.Fatalf("unexpected: %q", value)
+	}
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+}
+
+func TestDI_ProvideDuringInvoke(t *testing.T) {
+	t.Run("invoke", func(t *testing.T) {
+		di := New()
+		err := di.Invoke(func(d *DI) {
+			d.MustProvide(1)
+			if value := MustResolve[int](d); value != 1 {
+				t.Fatalf("unexpected: %d", value)
+			}
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	})
+
+	t.Run("constructor", func(t *testing.T) {
+		di := New().MustProvide(func(d *DI) (string, error) {
+			if err := d.Provide(func() int { return 2 }, WithEagerLoading()); err != nil {
+				return "", err
+			}
+			i, err := Resolve[int](d)
+			return strconv.Itoa(i), err
+		})
+
+		done := make(chan string, 1)
+		go func() {
+			done <- MustResolve[string](di)
+		}()
+
+		select {
+		case value := <-done:
+			if value != "2" {
+				t.Fatalf("unexpected: %q", value)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("deadlock")
+		}
+	})
+}
+
+func TestDI_InvokeAll(t *testing.T) {
+	di := New().MustProvide(1)
+
+	errTest2 := errors.New("test_err_2")
+	calls := 0
+	err := di.InvokeAll(
+		func(i int) error { calls++; return errTest },
+		func(i int) { calls++ },
+		func(s string) { calls++ },
+		func(i int) error { calls++; return errTest2 },
+	)
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, but got: %d", calls)
+	}
+	if !errors.Is(err, errTest) || !errors.Is(err, errTest2) || !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected all errors, but got: %v", err)
+	}
+
+	if err = di.InvokeAll(func(i int) {}, func() {}); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+}
+
+func TestDI_InjectScope(t *testing.T) {
+	parent := New().MustProvide(func(d *DI) (string, error) {
+		var s string
+		err := d.Invoke(func(f float64) { s = fmt.Sprint(f) })
+		return s, err
+	}, WithMultiInstance())
+
+	child := NewFrom(parent).MustProvide(1.5)
+	child.MustInvoke(func(s string, d *DI) {
+		if s != "1.5" {
+			t.Fatalf("unexpected: %q", s)
+		}
+		if d != child {
+			t.Fatalf("expected child container")
+		}
+	})
+
+	if err := parent.Invoke(func(s string) {}); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+}
+
+func TestDI_ProvideWithEagerLoadingIf(t *testing.T) {
+	t.Run("true", func(t *testing.T) {
+		err := New().Provide(func() (int, error) { return 0, errTest }, WithEagerLoadingIf(true))
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+
+	t.Run("false", func(t *testing.T) {
+		di := New()
+		di.EagerByDefault()
+		if err := di.Provide(func() (int, error) { return 0, errTest }, WithEagerLoadingIf(false)); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if _, err := Resolve[int](di); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+}
+
+func TestDI_EagerByDefault(t *testing.T) {
+	eagerCalls, lazyCalls := 0, 0
+	di := New()
+	di.EagerByDefault()
+
+	di.MustProvide(func() int { eagerCalls++; return 1 })
+	di.MustProvide(func() string { lazyCalls++; return "" }, WithLazy())
+
+	if eagerCalls != 1 {
+		t.Fatalf("expected eager construction, but got %d calls", eagerCalls)
+	}
+	if lazyCalls != 0 {
+		t.Fatalf("expected lazy construction, but got %d calls", lazyCalls)
+	}
+
+	di.MustInvoke(func(i int, s string) {})
+	if eagerCalls != 1 || lazyCalls != 1 {
+		t.Fatalf("unexpected calls: %d %d", eagerCalls, lazyCalls)
+	}
+
+	if err := di.Provide(func() (float64, error) { return 0, errTest }); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+}
+
+func TestDI_ProvideWithFinalizer(t *testing.T) {
+	t.Run("success_single_instance", func(t *testing.T) {
+		var finalized []any
+		di := New().MustProvide(func() int { return 1 }, WithFinalizer(func(value any) error {
+			finalized = append(finalized, value)
+			return nil
+		}))
+
+		di.MustInvoke(func(i1, i2 int) {})
+		di.MustInvoke(func(i int) {})
+		if !reflect.DeepEqual(finalized, []any{1}) {
+			t.Fatalf("unexpected: %v", finalized)
+		}
+	})
+
+	t.Run("success_round_robin", func(t *testing.T) {
+		var finalized []any
+		di := New().MustProvide(func() []int { return []int{1, 2} }, WithRoundRobin(),
+			WithFinalizer(func(value any) error {
+				finalized = append(finalized, value)
+				return nil
+			}))
+
+		di.MustInvoke(func(i1, i2, i3 int) {})
+		if !reflect.DeepEqual(finalized, []any{[]int{1, 2}}) {
+			t.Fatalf("unexpected: %v", finalized)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() int { calls++; return 1 }, WithFinalizer(func(value any) error {
+			return errTest
+		}))
+
+		for i := 0; i < 2; i++ {
+			if err := di.Invoke(func(i int) {}); !errors.Is(err, errTest) {
+				t.Fatalf("expected error: %q, but got: %v", errTest, err)
+			}
+		}
+		if calls != 2 {
+			t.Fatalf("expected value not to be cached, but got %d calls", calls)
+		}
+	})
+}
+
+type (
+	benchType1 struct{}
+	benchType2 struct{}
+	benchType3 struct{}
+	benchType4 struct{}
+)
+
+func BenchmarkDI_ResolveParallel(b *testing.B) {
+	di := New().
+		MustProvide(func() *benchType1 { return &benchType1{} }).
+		MustProvide(func() *benchType2 { return &benchType2{} }).
+		MustProvide(func() *benchType3 { return &benchType3{} }).
+		MustProvide(func() *benchType4 { return &benchType4{} })
+	for i := 0; i < 100; i++ {
+		di.MustProvide(i, WithName(fmt.Sprint(i)))
+	}
+
+	functions := []any{
+		func(*benchType1) {},
+		func(*benchType2) {},
+		func(*benchType3) {},
+		func(*benchType4) {},
+	}
+
+	var counter atomic.Int64
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		function := functions[counter.Add(1)%int64(len(functions))]
+		for pb.Next() {
+			if err := di.Invoke(function); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+func BenchmarkDI_Provide(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		di := New()
+		for j := 0; j < 1000; j++ {
+			di.MustProvide(j, WithName(fmt.Sprint(j)))
+		}
+	}
+}
+
+func TestDI_ResolveWithoutContainerLock(t *testing.T) {
+	di := New().MustProvide(func() *benchType1 { return &benchType1{} })
+
+	di.provideMutex.Lock()
+	defer di.provideMutex.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- di.Invoke(func(*benchType1) {})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolution is blocked by container lock")
+	}
+}
+
+func BenchmarkDI_ResolveDuringProvide(b *testing.B) {
+	di := New().MustProvide(func() *benchType1 { return &benchType1{} })
+	for i := 0; i < 1000; i++ {
+		di.MustProvide(i, WithName(fmt.Sprint(i)))
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				di.MustProvide(i, WithName(fmt.Sprint("writer ", i)))
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := di.Invoke(func(*benchType1) {}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDI_ProvideWithOnConstruct(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []ProviderOption
+		expected []any
+	}{
+		{name: "cached", expected: []any{1}},
+		{name: "multi_instance", options: []ProviderOption{WithMultiInstance()}, expected: []any{1, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			var constructed []any
+			di := New().MustProvide(func() int {
+				calls++
+				return calls
+			}, append(tc.options, WithOnConstruct(func(value any) {
+				constructed = append(constructed, value)
+			}))...)
+
+			MustResolve[int](di)
+			MustResolve[int](di)
+			if !reflect.DeepEqual(constructed, tc.expected) {
+				t.Fatalf("expected constructed: %v, but got: %v", tc.expected, constructed)
+			}
+		})
+	}
+
+	t.Run("round_robin", func(t *testing.T) {
+		var constructed []any
+		di := New().MustProvide(func() []int { return []int{1, 2} }, WithRoundRobin(),
+			WithOnConstruct(func(value any) {
+				constructed = append(constructed, value)
+			}))
+
+		MustResolve[int](di)
+		MustResolve[int](di)
+		if expected := []any{[]int{1, 2}}; !reflect.DeepEqual(constructed, expected) {
+			t.Fatalf("expected constructed: %v, but got: %v", expected, constructed)
+		}
+	})
+}
+
+func TestDI_ProvideWithConstructorArgs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New().
+			MustProvide("container").
+			MustProvide(func(s string, port int, host string) bool {
+				if s != "container" || port != 8080 || host != "container" {
+					t.Fatalf("unexpected: %q, %d, %q", s, port, host)
+				}
+				return true
+			}, WithConstructorArgs(8080, "arg"))
+
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		di.MustInvoke(func(b bool) {})
+	})
+
+	t.Run("success_position", func(t *testing.T) {
+		di := New().MustProvide(func(i1 int, s string, i2 int) bool {
+			if i1 != 1 || s != "arg" || i2 != 2 {
+				t.Fatalf("unexpected: %d, %q, %d", i1, s, i2)
+			}
+			return true
+		}, WithConstructorArgs(1, 2, "arg"))
+
+		di.MustInvoke(func(b bool) {})
+	})
+
+	t.Run("error_missing", func(t *testing.T) {
+		di := New().MustProvide(func(i int, s string) bool { return true }, WithConstructorArgs(1))
+
+		if err := di.Invoke(func(b bool) {}); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if err := di.Validate(); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+}
+
+func TestDI_SetMaxDepth(t *testing.T) {
+	parent := New().
+		MustProvide(1).
+		MustProvide(func(i int) string { return "" }).
+		MustProvide(func(s string) float64 { return 0 }).
+		MustProvide(func(f float64) bool { return true })
+	parent.SetMaxDepth(3)
+	di := NewFrom(parent)
+
+	err := di.Invoke(func(b bool) {})
+	if !errors.Is(err, ErrMaxDepthExceeded) {
+		t.Fatalf("expected error: %q, but got: %v", ErrMaxDepthExceeded, err)
+	}
+	if !strings.Contains(err.Error(), "bool -> float64 -> string -> int") {
+		t.Fatalf("expected chain in error, but got: %q", err)
+	}
+
+	di.SetMaxDepth(4)
+	if err = di.Invoke(func(b bool) {}); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+}
+
+func TestDI_SetOnProvide(t *testing.T) {
+	parent := New()
+	parent.SetOnProvide(func(pType reflect.Type) error {
+		if pType == typeOf[string]() {
+			return errTest
+		}
+		return nil
+	})
+	di := NewFrom(parent)
+
+	if err := di.Provide(1); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := di.Provide(func() string { return "" }); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+	if err := parent.Provide("test"); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+	if HasType[string](di) {
+		t.Fatalf("unexpected provider")
+	}
+
+	di.SetOnProvide(func(pType reflect.Type) error { return nil })
+	if err := di.Provide("test"); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+}
+
+func TestDI_Reset(t *testing.T) {
+	calls := 0
+	di := New().
+		MustProvide(func() int { calls++; return calls }).
+		MustProvide("test")
+
+	if i := MustResolve[int](di); i != 1 {
+		t.Fatalf("unexpected: %d", i)
+	}
+	if i := MustResolve[int](di); i != 1 {
+		t.Fatalf("expected cached value, but got: %d", i)
+	}
+
+	if !ResetType[int](di) {
+		t.Fatalf("expected reset")
+	}
+	if i := MustResolve[int](di); i != 2 {
+		t.Fatalf("expected new value, but got: %d", i)
+	}
+	if i := MustResolve[int](di); i != 2 {
+		t.Fatalf("expected cached value, but got: %d", i)
+	}
+
+	if di.Reset(reflect.TypeOf("")) {
+		t.Fatalf("unexpected reset of value provider")
+	}
+	if ResetType[float64](di) {
+		t.Fatalf("unexpected reset of not existing provider")
+	}
+}
+
+func TestDI_ResetAll(t *testing.T) {
+	calls := 0
+	cleaned := 0
+	di := New().
+		MustProvide(func() (int, func()) {
+			calls++
+			return calls, func() { cleaned++ }
+		}).
+		MustProvide(func(i int) string { return strconv.Itoa(i) }).
+		MustProvide(func(i int) bool { return true }, WithGroup()).
+		MustProvide(2.5)
+
+	di.MustInvoke(func(s string, b []bool, f float64) {})
+	if calls != 1 {
+		t.Fatalf("unexpected: %d", calls)
+	}
+
+	di.ResetAll()
+	if cleaned != 1 {
+		t.Fatalf("unexpected: %d", cleaned)
+	}
+
+	if value := MustResolve[string](di); value != "2" {
+		t.Fatalf("expected new value, but got: %q", value)
+	}
+	if value := MustResolve[int](di); value != 2 {
+		t.Fatalf("expected cached value, but got: %d", value)
+	}
+	if value := MustResolve[float64](di); value != 2.5 {
+		t.Fatalf("unexpected: %f", value)
+	}
+
+	if err := di.Close(); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if cleaned != 2 {
+		t.Fatalf("unexpected: %d", cleaned)
+	}
+}
+
+func TestDI_ReplaceConstructor(t *testing.T) {
+	t.Run("keeps_cache", func(t *testing.T) {
+		di := New().MustProvide(func() int { return 1 })
+		if i := MustResolve[int](di); i != 1 {
+			t.Fatalf("unexpected: %d", i)
+		}
+
+		if err := di.ReplaceConstructor(typeOf[int](), func(s string) (string, int) { return s, len(s) }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if i := MustResolve[int](di); i != 1 {
+			t.Fatalf("expected cached value, but got: %d", i)
+		}
+
+		di.MustProvide("test")
+		if i := MustResolve[int](di); i != 1 {
+			t.Fatalf("expected cached value, but got: %d", i)
+		}
+
+		ResetType[int](di)
+		if i := MustResolve[int](di); i != 4 {
+			t.Fatalf("expected new value, but got: %d", i)
+		}
+	})
+
+	t.Run("not_constructed", func(t *testing.T) {
+		cleaned := false
+		di := New().MustProvide(func() int { return 1 })
+		if err := di.ReplaceConstructor(typeOf[int](), func() (int, func()) {
+			return 2, func() { cleaned = true }
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if i := MustResolve[int](di); i != 2 {
+			t.Fatalf("unexpected: %d", i)
+		}
+		if err := di.Close(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if !cleaned {
+			t.Fatal("expected cleanup")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		di := New().
+			MustProvide(func() int { return 1 }).
+			MustProvide("test").
+			MustProvide(func() []bool { return []bool{true} }, WithRoundRobin())
+
+		err := di.ReplaceConstructor(typeOf[float64](), func() float64 { return 1 })
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if err := di.ReplaceConstructor(typeOf[string](), func() string { return "" }); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := di.ReplaceConstructor(typeOf[bool](), func() []bool { return nil }); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := di.ReplaceConstructor(typeOf[int](), 1); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := di.ReplaceConstructor(typeOf[int](), func() uint { return 1 }); err == nil {
+			t.Fatal("expected error")
+		}
+		if i := MustResolve[int](di); i != 1 {
+			t.Fatalf("unexpected: %d", i)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		di := New().MustProvide(func() int { return 1 })
+		typ := typeOf[int]()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if err := di.ReplaceConstructor(typ, func() int { return 2 }); err != nil {
+					t.Errorf("unexpected error: %q", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				di.Describe(typ)
+				di.Reset(typ)
+				_ = di.Validate()
+				_ = di.EagerInitAll(false)
+			}
+		}()
+		wg.Wait()
+	})
+}
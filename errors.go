@@ -0,0 +1,193 @@
+package mdi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrProviderAlreadyExists indicates that provider of the same type already exists
+	ErrProviderAlreadyExists = errors.New("provider already exists")
+
+	// ErrProviderNotFound indicates that provider of requested type was not found
+	ErrProviderNotFound = errors.New("provider not found")
+
+	// ErrCantRoundRobin indicates that provided value can't be used for round-robin
+	ErrCantRoundRobin = errors.New("can't round-robin")
+
+	// ErrNotAFunction indicates that invoked value is not a function
+	ErrNotAFunction = errors.New("not a function")
+
+	// ErrNotImplements indicates that provided type doesn't implement interface
+	ErrNotImplements = errors.New("not implements")
+
+	// ErrTimeout indicates that construction of dependency took too long
+	ErrTimeout = errors.New("timeout")
+
+	// ErrCycleDetected indicates that dependencies have a cycle
+	ErrCycleDetected = errors.New("cycle detected")
+
+	// ErrAmbiguousProvider indicates that several providers are suitable for requested type
+	ErrAmbiguousProvider = errors.New("ambiguous provider")
+
+	// ErrMaxDepthExceeded indicates that dependencies are nested deeper than allowed
+	ErrMaxDepthExceeded = errors.New("max depth exceeded")
+
+	// ErrPanicked indicates that called function panicked
+	ErrPanicked = errors.New("panicked")
+)
+
+// NotFoundError represents an error of not found provider for parameter, matches [ErrProviderNotFound]
+type NotFoundError struct {
+	// Index is zero-based index of parameter (or field) that failed to be resolved
+	Index int
+	// Type is type of parameter
+	Type reflect.Type
+	// Name is name of provider requested for parameter (empty if provider is unnamed)
+	Name string
+	// Key is custom key of provider requested for parameter (nil if provider has no custom key)
+	Key any
+}
+
+// Error returns error message
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found provider for %d parameter of %s", e.Index+1,
+		providerKey{pType: e.Type, name: e.Name, custom: e.Key}.describe())
+}
+
+// Is reports whether target error is [ErrProviderNotFound]
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrProviderNotFound
+}
+
+// wrappedError represents an error with custom message that wraps another error
+type wrappedError struct {
+	message string
+	err     error
+}
+
+// Error returns error message
+func (e *wrappedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns wrapped error
+func (e *wrappedError) Unwrap() error {
+	return e.err
+}
+
+// newErrorProviderAlreadyExists returns an error indicating that the provider of this type already exists
+func newErrorProviderAlreadyExists(key providerKey) error {
+	return &wrappedError{
+		message: fmt.Sprintf("provider of %s already exists", key.describe()),
+		err:     ErrProviderAlreadyExists,
+	}
+}
+
+// newErrorProviderNotFound returns an error indicating that the provider for parameter was not found
+func newErrorProviderNotFound(i int, key providerKey) error {
+	return &NotFoundError{Index: i, Type: key.pType, Name: key.name, Key: key.custom}
+}
+
+// newErrorProviderCantRoundRobin returns an error indicating that the provider of this type is not suitable for
+// round-robin
+func newErrorProviderCantRoundRobin(pType reflect.Type) error {
+	return &wrappedError{
+		message: fmt.Sprintf("can't round-robin value of type %q, must be a slice, an array or a map", pType.String()),
+		err:     ErrCantRoundRobin,
+	}
+}
+
+// newErrorEmptyRoundRobin returns an error indicating that the value used for round-robin has no elements
+func newErrorEmptyRoundRobin(pType reflect.Type) error {
+	return &wrappedError{
+		message: fmt.Sprintf("can't round-robin empty value of type %q", pType.String()),
+		err:     ErrCantRoundRobin,
+	}
+}
+
+// newErrorRoundRobinWeights returns an error indicating that number of weights doesn't match number of elements
+func newErrorRoundRobinWeights(pType reflect.Type, weights int, elements int) error {
+	return &wrappedError{
+		message: fmt.Sprintf("can't round-robin value of type %q with %d weights, it has %d elements",
+			pType.String(), weights, elements),
+		err: ErrCantRoundRobin,
+	}
+}
+
+// newErrorNotAFunction returns an error indicating that invoked value is not a function
+func newErrorNotAFunction() error {
+	return &wrappedError{
+		message: "can't invoke a non-function or nil value",
+		err:     ErrNotAFunction,
+	}
+}
+
+// newErrorNotImplements returns an error indicating that the type doesn't implement interface, methods of interface
+// that type is missing are listed in the message
+func newErrorNotImplements(pType reflect.Type, iType reflect.Type) error {
+	typeName := "nil"
+	if pType != nil {
+		typeName = pType.String()
+	}
+
+	message := fmt.Sprintf("type %q doesn't implement interface %q", typeName, iType.String())
+	if missing := missingMethods(pType, iType); len(missing) != 0 {
+		message += ", missing methods: " + strings.Join(missing, ", ")
+	}
+
+	return &wrappedError{
+		message: message,
+		err:     ErrNotImplements,
+	}
+}
+
+// newErrorTimeout returns an error indicating that construction of dependency took longer than timeout
+func newErrorTimeout(timeout time.Duration) error {
+	return &wrappedError{
+		message: fmt.Sprintf("construction timed out after %s", timeout),
+		err:     ErrTimeout,
+	}
+}
+
+// newErrorCycleDetected returns an error indicating that dependency cycle was found
+func newErrorCycleDetected(chain []providerKey) error {
+	return &wrappedError{
+		message: fmt.Sprintf("cycle detected: %s", formatChain(chain)),
+		err:     ErrCycleDetected,
+	}
+}
+
+// newErrorAmbiguousProvider returns an error indicating that several providers are assignable to the parameter
+func newErrorAmbiguousProvider(i int, key providerKey, matches []providerKey) error {
+	matchTypes := make([]string, 0, len(matches))
+	for _, match := range matches {
+		matchTypes = append(matchTypes, strconv.Quote(match.String()))
+	}
+
+	return &wrappedError{
+		message: fmt.Sprintf("ambiguous provider for %d parameter of %s, assignable types: %s",
+			i+1, key.describe(), strings.Join(matchTypes, ", ")),
+		err: ErrAmbiguousProvider,
+	}
+}
+
+// newErrorMaxDepthExceeded returns an error indicating that resolution chain is longer than max depth
+func newErrorMaxDepthExceeded(maxDepth int, chain []providerKey) error {
+	return &wrappedError{
+		message: fmt.Sprintf("max resolution depth %d exceeded: %s", maxDepth, formatChain(chain)),
+		err:     ErrMaxDepthExceeded,
+	}
+}
+
+// newErrorPanicked returns an error indicating that function panicked with value
+func newErrorPanicked(fType reflect.Type, value any) error {
+	return &wrappedError{
+		message: fmt.Sprintf("function %q panicked: %v", fType.String(), value),
+		err:     ErrPanicked,
+	}
+}
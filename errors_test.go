@@ -0,0 +1,159 @@
+package mdi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCodeError interface {
+	error
+	Code() int
+}
+
+type testCodeErr struct{}
+
+func (testCodeErr) Error() string { return "code error" }
+
+func (testCodeErr) Code() int { return 1 }
+
+func TestErrors(t *testing.T) {
+	t.Run("already_exists", func(t *testing.T) {
+		err := New().MustProvide(1).Provide(2)
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if err.Error() != `provider of type "int" already exists` {
+			t.Fatalf("unexpected message: %q", err)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		err := New().MustProvide(1).Invoke(func(i int, s string) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if err.Error() != `not found provider for 2 parameter of type "string"` {
+			t.Fatalf("unexpected message: %q", err)
+		}
+
+		var notFoundErr *NotFoundError
+		if !errors.As(err, &notFoundErr) {
+			t.Fatalf("expected not found error, but got: %v", err)
+		}
+		if notFoundErr.Type != reflect.TypeOf("") {
+			t.Fatalf("unexpected type: %s", notFoundErr.Type)
+		}
+		if notFoundErr.Index != 1 {
+			t.Fatalf("unexpected index: %d", notFoundErr.Index)
+		}
+	})
+
+	t.Run("not_found_nested", func(t *testing.T) {
+		err := New().MustProvide(func(s string) int { return 0 }).Invoke(func(i int) {})
+		var notFoundErr *NotFoundError
+		if !errors.As(err, &notFoundErr) || notFoundErr.Type != reflect.TypeOf("") {
+			t.Fatalf("expected not found error, but got: %v", err)
+		}
+	})
+
+	t.Run("resolution_path", func(t *testing.T) {
+		di := New().
+			MustProvide(func(f float64) bool { return true }).
+			MustProvide(func(s string) float64 { return 0 }).
+			MustProvide(func(i int) string { return "" })
+
+		err := di.Invoke(func(b bool) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if !strings.Contains(err.Error(),
+			`resolving bool -> float64 -> string: not found provider for 1 parameter of type "int"`) {
+			t.Fatalf("unexpected message: %q", err)
+		}
+
+		err = New().MustProvide(func() (int, error) { return 0, errTest }).Invoke(func(i int) {})
+		if !errors.Is(err, errTest) || !strings.Contains(err.Error(), "resolving int: "+errTest.Error()) {
+			t.Fatalf("unexpected message: %q", err)
+		}
+	})
+
+	t.Run("custom_error_type", func(t *testing.T) {
+		di := New().
+			MustProvide(func() (int, testCodeError) { return 1, nil }).
+			MustProvide(func() (string, testCodeError) { return "", testCodeErr{} })
+
+		if HasType[testCodeError](di) {
+			t.Fatalf("unexpected error provider")
+		}
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+
+		_, err := Resolve[string](di)
+		var codeErr testCodeError
+		if !errors.As(err, &codeErr) || codeErr.Code() != 1 {
+			t.Fatalf("expected code error, but got: %v", err)
+		}
+
+		if err = ProvideValue[testCodeError](New(), testCodeErr{}); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+
+	t.Run("only_error", func(t *testing.T) {
+		err := New().Provide(func() error { return nil })
+		if err == nil || !strings.Contains(err.Error(), "constructor returns only error, nothing to provide") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("cant_round_robin", func(t *testing.T) {
+		err := New().Provide(1, WithRoundRobin())
+		if !errors.Is(err, ErrCantRoundRobin) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCantRoundRobin, err)
+		}
+	})
+
+	t.Run("not_a_function", func(t *testing.T) {
+		err := New().Invoke(1)
+		if !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		if err := New().Provide(nil); err == nil || err.Error() != "can't provide nil" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := New().Provide((func() int)(nil)); err == nil || err.Error() != "can't provide nil function" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := ProvideFunc[int](New(), nil); err == nil || err.Error() != "can't provide nil function" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := New().ProvideAsMany((func() *bytes.Buffer)(nil), []any{(*io.Writer)(nil), (*io.Reader)(nil)})
+		if err == nil || err.Error() != "can't provide nil function" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err = New().MustProvide(1).Decorate((func(int) int)(nil)); !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+		if err := New().Invoke(nil); !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+		if err := New().Invoke((func())(nil)); !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+	})
+
+	t.Run("cycle_detected", func(t *testing.T) {
+		err := New().MustProvide(func(i int) int { return i }).Invoke(func(i int) {})
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+	})
+}
@@ -0,0 +1,329 @@
+package mdi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DOT returns dependency graph of container in Graphviz DOT format, edges go from a provider type to each type it
+// depends on, value providers are represented as leaf nodes
+func (d *DI) DOT() string {
+	nodes := map[string]struct{}{}
+	var edges []string
+	d.providers().each(func(key providerKey, p *provider) {
+		node := strconv.Quote(key.String())
+		nodes[node] = struct{}{}
+		for _, dep := range p.dependencies() {
+			edges = append(edges, node+" -> "+strconv.Quote(dep.String()))
+		}
+		if key == diKey {
+			nodes[strconv.Quote(resolverKey.String())] = struct{}{}
+			nodes[strconv.Quote(providerIfaceKey.String())] = struct{}{}
+		}
+	})
+
+	nodeNames := make([]string, 0, len(nodes))
+	for node := range nodes {
+		nodeNames = append(nodeNames, node)
+	}
+	sort.Strings(nodeNames)
+	sort.Strings(edges)
+
+	sb := &strings.Builder{}
+	sb.WriteString("digraph {\n")
+	for _, node := range nodeNames {
+		sb.WriteString("\t" + node + ";\n")
+	}
+	for _, edge := range edges {
+		sb.WriteString("\t" + edge + ";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Validate checks that dependencies of all function providers of container (and its parents) can be resolved and have
+// no cycles, it doesn't call any constructors, returns all found errors joined
+func (d *DI) Validate() error {
+	var errs []error
+	validated := map[*provider]bool{}
+	for di := d; di != nil; di = di.parent {
+		providers := di.providers()
+		for _, key := range sortedKeys(providers) {
+			p, _ := providers.get(key)
+			errs = append(errs, di.walkProvider(key, p, &resolution{}, validated, nil)...)
+		}
+
+		for _, member := range di.load().collectionMembers() {
+			errs = append(errs, di.walkProvider(member.key, member.provider, &resolution{}, validated, nil)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// walkProvider recursively checks that all dependencies of provider can be resolved, if plan is set, keys of function
+// providers that have to be constructed are appended to it in construction order
+func (d *DI) walkProvider(
+	key providerKey, p *provider, res *resolution, visited map[*provider]bool, plan *[]providerKey,
+) []error {
+	if visited[p] {
+		return nil
+	}
+
+	if plan != nil && p.isCached() {
+		visited[p] = true
+		return nil
+	}
+
+	res, err := res.push(key)
+	if err != nil {
+		return []error{err}
+	}
+
+	errs := d.walkDependencies(key.describe(), p.dependencies(), p.args, res, visited, plan)
+
+	visited[p] = true
+	if plan != nil && p.isFunction() {
+		*plan = append(*plan, key)
+	}
+	return errs
+}
+
+// walkDependencies calls [DI.walkProvider] for providers of each dependency, dependencies without providers can be
+// satisfied by constructor arguments, owner is used only in error messages
+func (d *DI) walkDependencies(
+	owner string, deps []providerKey, args []reflect.Value, res *resolution, visited map[*provider]bool,
+	plan *[]providerKey,
+) []error {
+	usedArgs := make([]bool, len(args))
+
+	var errs []error
+	for i, dep := range deps {
+		depProvider, depOwner, ok := d.lookupProvider(dep)
+		if ok {
+			errs = append(errs, depOwner.walkProvider(dep, depProvider, res, visited, plan)...)
+			continue
+		}
+		if isContainerKey(dep) {
+			continue
+		}
+
+		members := d.sliceGroupMembers(dep)
+		if len(members) == 0 {
+			matchKey, match, matchOwner, err := d.lookupAssignable(i, dep)
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("provider of %s: %w", owner, err))
+			case match != nil:
+				errs = append(errs, matchOwner.walkProvider(matchKey, match, res, visited, plan)...)
+			default:
+				if _, ok = takeArg(args, usedArgs, dep.pType); !ok {
+					errs = append(errs, fmt.Errorf("provider of %s: %w", owner, newErrorProviderNotFound(i, dep)))
+				}
+			}
+			continue
+		}
+		for _, member := range members {
+			errs = append(errs, member.owner.walkProvider(dep, member.provider, res, visited, plan)...)
+		}
+	}
+	return errs
+}
+
+// ResolvePlan returns types of providers that would be constructed to invoke function in construction order, without
+// calling any constructors, already cached providers and their dependencies are not included
+func (d *DI) ResolvePlan(function any) ([]reflect.Type, error) {
+	if function == nil || reflect.TypeOf(function).Kind() != reflect.Func {
+		return nil, newErrorNotAFunction()
+	}
+
+	var plan []providerKey
+	errs := d.walkDependencies(
+		"function "+reflect.TypeOf(function).String(), functionDependencies(reflect.TypeOf(function), nil),
+		nil, newResolution(d), map[*provider]bool{}, &plan,
+	)
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	types := make([]reflect.Type, 0, len(plan))
+	seen := make(map[reflect.Type]bool, len(plan))
+	for _, key := range plan {
+		if seen[key.pType] {
+			continue
+		}
+		seen[key.pType] = true
+		types = append(types, key.pType)
+	}
+	return types, nil
+}
+
+// MissingFor returns types of parameters of function (or fields of [In] parameters) that can't be resolved from
+// container or any of its parents, it doesn't call any constructors and doesn't check dependencies of providers
+func (d *DI) MissingFor(function any) []reflect.Type {
+	fType := reflect.TypeOf(function)
+	if fType == nil || fType.Kind() != reflect.Func {
+		return nil
+	}
+
+	var missing []reflect.Type
+	for i, dep := range functionDependencies(fType, nil) {
+		if !d.canResolve(i, dep) {
+			missing = append(missing, dep.pType)
+		}
+	}
+	return missing
+}
+
+// canResolve reports whether there is a provider, group members or assignable provider for dependency
+func (d *DI) canResolve(i int, dep providerKey) bool {
+	if _, _, ok := d.lookupProvider(dep); ok || isContainerKey(dep) {
+		return true
+	}
+	if len(d.sliceGroupMembers(dep)) != 0 {
+		return true
+	}
+	_, match, _, err := d.lookupAssignable(i, dep)
+	return err == nil && match != nil
+}
+
+// EagerInitAll constructs all function providers, group and keyed members of container (except multi-instance ones) in
+// dependency order, so construction errors surface right away, if failFast is set the first error is returned,
+// otherwise all errors joined
+func (d *DI) EagerInitAll(failFast bool) error {
+	var errs []error
+	for _, item := range d.constructionOrder() {
+		if !item.provider.isFunction() || item.provider.disableCache {
+			continue
+		}
+
+		if _, err := item.provider.construct(d, newResolution(d, item.key)); err != nil {
+			err = fmt.Errorf("failed to eagerly load value of %s: %w", item.key.describe(), err)
+			if failFast {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// EagerInitAllAsync is like [DI.EagerInitAll], but constructs each provider in its own goroutine, construction of
+// provider starts after its dependencies from container are constructed, errors are sent to returned channel as they
+// occur, channel is closed after all providers are processed
+func (d *DI) EagerInitAllAsync() <-chan error {
+	order := d.constructionOrder()
+
+	errs := make(chan error, len(order))
+	done := make([]chan struct{}, len(order))
+	positions := make(map[providerKey]int, len(order))
+	for i, item := range order {
+		done[i] = make(chan struct{})
+		if _, ok := positions[item.key]; !ok {
+			positions[item.key] = i
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, item := range order {
+		wg.Add(1)
+		go func(i int, key providerKey, p *provider) {
+			defer wg.Done()
+			defer close(done[i])
+
+			// Only dependencies that go before provider are awaited, so providers with cyclic dependencies don't
+			// wait for each other forever, instead, construction reports the cycle
+			for _, dep := range p.dependencies() {
+				if position, ok := positions[dep]; ok && position < i {
+					<-done[position]
+				}
+			}
+
+			if !p.isFunction() || p.disableCache {
+				return
+			}
+			if _, err := p.construct(d, newResolution(d, key)); err != nil {
+				errs <- fmt.Errorf("failed to eagerly load value of %s: %w", key.describe(), err)
+			}
+		}(i, item.key, item.provider)
+	}
+
+	go func() {
+		wg.Wait()
+		close(errs)
+	}()
+	return errs
+}
+
+// providerEntry represents provider alongside with key it's described by
+type providerEntry struct {
+	key      providerKey
+	provider *provider
+}
+
+// constructionOrder returns providers of container ordered so that dependencies go before providers that depend on
+// them, followed by members of groups and keyed collections of container
+func (d *DI) constructionOrder() []providerEntry {
+	s := d.load()
+	providers := s.provide
+	order := make([]providerEntry, 0, providers.len())
+	visited := make(map[providerKey]bool, providers.len())
+
+	var visit func(key providerKey)
+	visit = func(key providerKey) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+
+		p, _ := providers.get(key)
+		for _, dep := range p.dependencies() {
+			if _, ok := providers.get(dep); ok {
+				visit(dep)
+			}
+		}
+		order = append(order, providerEntry{key: key, provider: p})
+	}
+
+	for _, key := range sortedKeys(providers) {
+		visit(key)
+	}
+	return append(order, s.collectionMembers()...)
+}
+
+// collectionMembers returns members of groups and keyed collections of state sorted by type, members of groups are
+// described by slice of their type and members of keyed collections by their type with key
+func (s *state) collectionMembers() []providerEntry {
+	var members []providerEntry
+	s.groups.each(func(pType reflect.Type, providers []*provider) {
+		for _, p := range providers {
+			members = append(members, providerEntry{key: providerKey{pType: reflect.SliceOf(pType)}, provider: p})
+		}
+	})
+	s.keyed.each(func(pType reflect.Type, providers []*provider) {
+		for _, p := range providers {
+			members = append(members, providerEntry{key: providerKey{pType: pType, custom: p.mapKey}, provider: p})
+		}
+	})
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].key.pType.String() < members[j].key.pType.String()
+	})
+	return members
+}
+
+// sortedKeys returns keys of providers sorted by their string representation
+func sortedKeys(providers provideMap) []providerKey {
+	keys := make([]providerKey, 0, providers.len())
+	providers.each(func(key providerKey, _ *provider) {
+		keys = append(keys, key)
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
@@ -0,0 +1,340 @@
+package mdi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDI_DOT(t *testing.T) {
+	di := New().
+		MustProvide(1).
+		MustProvide(func(i int) string { return "" }).
+		MustProvide(func(s string, i int) float64 { return 0 })
+
+	dot := di.DOT()
+	t.Log(dot)
+
+	expected := []string{
+		"digraph {\n",
+		"\t\"int\";\n",
+		"\t\"*mdi.DI\";\n",
+		"\t\"mdi.Resolver\";\n",
+		"\t\"mdi.Provider\";\n",
+		"\t\"string\" -> \"int\";\n",
+		"\t\"float64\" -> \"int\";\n",
+		"\t\"float64\" -> \"string\";\n",
+	}
+	for _, line := range expected {
+		if !strings.Contains(dot, line) {
+			t.Fatalf("expected %q in DOT", line)
+		}
+	}
+	if strings.Contains(dot, "\"int\" ->") {
+		t.Fatalf("unexpected edges from value provider")
+	}
+
+	if dot != di.DOT() {
+		t.Fatalf("expected deterministic output")
+	}
+}
+
+func TestDI_Validate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		called := false
+		parent := New().MustProvide(1)
+		di := NewFrom(parent).
+			MustProvide(func(i int) string { called = true; return "" }).
+			MustProvide(func(s string, i int, _ *DI) float64 { called = true; return 0 })
+
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if called {
+			t.Fatalf("constructors should not be called")
+		}
+	})
+
+	t.Run("error_missing", func(t *testing.T) {
+		di := New().
+			MustProvide(func(i int) string { return "" }).
+			MustProvide(func(s string, b bool) float64 { return 0 })
+
+		err := di.Validate()
+		if err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		t.Logf("validate error: %q", err)
+		if !strings.Contains(err.Error(), `not found provider for 1 parameter of type "int"`) ||
+			!strings.Contains(err.Error(), `not found provider for 2 parameter of type "bool"`) {
+			t.Fatalf("expected all missing parameters, but got: %q", err)
+		}
+	})
+
+	t.Run("error_keyed", func(t *testing.T) {
+		di := New().MustProvide(func(b bool) string { return "" }, WithKey("k"))
+
+		err := di.Validate()
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if !strings.Contains(err.Error(), `type "string" with key k`) {
+			t.Fatalf("expected keyed member in error, but got: %q", err)
+		}
+	})
+
+	t.Run("error_cycle", func(t *testing.T) {
+		di := New().
+			MustProvide(func(i int) string { return "" }).
+			MustProvide(func(s string) int { return 0 })
+
+		err := di.Validate()
+		if err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		t.Logf("validate error: %q", err)
+		if !strings.Contains(err.Error(), "cycle detected") {
+			t.Fatalf("expected cycle error, but got: %q", err)
+		}
+
+		err = di.Invoke(func(s string) {})
+		if err == nil || !strings.Contains(err.Error(), "cycle detected") {
+			t.Fatalf("expected runtime cycle error, but got: %v", err)
+		}
+	})
+}
+
+func TestDI_ResolvePlan(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		called := false
+		di := New().
+			MustProvide(1).
+			MustProvide(func(i int) string { called = true; return "" }).
+			MustProvide(func(s string, i int) float64 { called = true; return 0 }).
+			MustProvide(func(f float64, s string) bool { called = true; return false })
+
+		plan, err := di.ResolvePlan(func(b bool, _ *DI) {})
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if called {
+			t.Fatalf("constructors should not be called")
+		}
+
+		expected := []reflect.Type{typeOf[string](), typeOf[float64](), typeOf[bool]()}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Fatalf("expected plan: %v, but got: %v", expected, plan)
+		}
+	})
+
+	t.Run("success_cached", func(t *testing.T) {
+		di := New().
+			MustProvide(1).
+			MustProvide(func(i int) string { return "" }).
+			MustProvide(func(s string, i int) float64 { return 0 }).
+			MustProvide(func(f float64) bool { return false })
+
+		if err := di.Invoke(func(f float64) {}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		plan, err := di.ResolvePlan(func(b bool) {})
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		expected := []reflect.Type{typeOf[bool]()}
+		if !reflect.DeepEqual(plan, expected) {
+			t.Fatalf("expected plan: %v, but got: %v", expected, plan)
+		}
+	})
+
+	t.Run("error_missing", func(t *testing.T) {
+		di := New().MustProvide(func(i int) string { return "" })
+
+		_, err := di.ResolvePlan(func(s string) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_cycle", func(t *testing.T) {
+		di := New().
+			MustProvide(func(i int) string { return "" }).
+			MustProvide(func(s string) int { return 0 })
+
+		_, err := di.ResolvePlan(func(s string) {})
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+	})
+
+	t.Run("error_not_a_function", func(t *testing.T) {
+		_, err := New().ResolvePlan(1)
+		if !errors.Is(err, ErrNotAFunction) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotAFunction, err)
+		}
+	})
+}
+
+func TestDI_MissingFor(t *testing.T) {
+	constructed := false
+	di := New().MustProvide(func() int {
+		constructed = true
+		return 1
+	})
+
+	t.Run("one_missing", func(t *testing.T) {
+		missing := di.MissingFor(func(int, string) {})
+		if expected := []reflect.Type{typeOf[string]()}; !reflect.DeepEqual(missing, expected) {
+			t.Fatalf("expected missing: %v, but got: %v", expected, missing)
+		}
+	})
+
+	t.Run("none_missing", func(t *testing.T) {
+		if missing := di.Scope().MissingFor(func(int, *DI, Resolver) {}); len(missing) != 0 {
+			t.Fatalf("unexpected missing: %v", missing)
+		}
+	})
+
+	t.Run("not_a_function", func(t *testing.T) {
+		if missing := di.MissingFor(1); missing != nil {
+			t.Fatalf("unexpected missing: %v", missing)
+		}
+	})
+
+	if constructed {
+		t.Fatal("unexpected construction")
+	}
+}
+
+func TestDI_EagerInitAll(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var calls []string
+		di := New().
+			MustProvide(func(i int) string { calls = append(calls, "string"); return "" }).
+			MustProvide(func() int { calls = append(calls, "int"); return 1 }).
+			MustProvide(func() float64 { calls = append(calls, "float64"); return 0 }, WithMultiInstance())
+
+		if err := di.EagerInitAll(true); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if strings.Join(calls, ",") != "int,string" {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+
+		di.MustInvoke(func(s string, i int) {})
+		if strings.Join(calls, ",") != "int,string" {
+			t.Fatalf("expected cached values, but got calls: %v", calls)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errTest2 := errors.New("test_err_2")
+		di := New().
+			MustProvide(func() (int, error) { return 0, errTest }).
+			MustProvide(func() (string, error) { return "", errTest2 })
+
+		err := di.EagerInitAll(true)
+		if !errors.Is(err, errTest) || errors.Is(err, errTest2) {
+			t.Fatalf("expected only first error, but got: %v", err)
+		}
+
+		err = di.EagerInitAll(false)
+		if !errors.Is(err, errTest) || !errors.Is(err, errTest2) {
+			t.Fatalf("expected all errors, but got: %v", err)
+		}
+	})
+
+	t.Run("group", func(t *testing.T) {
+		var calls int
+		di := New().
+			MustProvide(func() int { calls++; return 1 }, WithGroup()).
+			MustProvide(func() (int, error) { calls++; return 0, errTest }, WithGroup())
+
+		err := di.EagerInitAll(false)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+		if calls != 2 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+	})
+
+	t.Run("keyed", func(t *testing.T) {
+		var calls int
+		di := New().
+			MustProvide(func() int { calls++; return 1 }, WithKey("a")).
+			MustProvide(func() (int, error) { calls++; return 0, errTest }, WithKey("b"))
+
+		err := di.EagerInitAll(false)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+		if calls != 2 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+	})
+}
+
+func TestDI_EagerInitAllAsync(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var calls atomic.Int32
+		di := New().
+			MustProvide(func(i int, f float64) string { calls.Add(1); return "" }).
+			MustProvide(func() int { calls.Add(1); return 1 }).
+			MustProvide(func() float64 { calls.Add(1); return 0 }).
+			MustProvide(func() bool { calls.Add(1); return true }, WithMultiInstance())
+
+		for err := range di.EagerInitAllAsync() {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if calls.Load() != 3 {
+			t.Fatalf("unexpected: %d", calls.Load())
+		}
+
+		di.MustInvoke(func(s string, i int, f float64) {})
+		if calls.Load() != 3 {
+			t.Fatalf("expected cached values, but got calls: %d", calls.Load())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		di := New().
+			MustProvide(func() (int, error) { return 0, errTest }).
+			MustProvide(func(i int) string { return "" }).
+			MustProvide(func() float64 { return 0 }).
+			MustProvide(func(b bool) bool { return b })
+
+		var errs []error
+		for err := range di.EagerInitAllAsync() {
+			errs = append(errs, err)
+		}
+		if len(errs) != 3 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if err := errors.Join(errs...); !errors.Is(err, errTest) || !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("unexpected errors: %v", err)
+		}
+	})
+
+	t.Run("group", func(t *testing.T) {
+		var calls atomic.Int32
+		di := New().
+			MustProvide(func() int { calls.Add(1); return 1 }, WithGroup()).
+			MustProvide(func() (int, error) { calls.Add(1); return 0, errTest }, WithGroup())
+
+		var errs []error
+		for err := range di.EagerInitAllAsync() {
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], errTest) {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if calls.Load() != 2 {
+			t.Fatalf("unexpected: %d", calls.Load())
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package mdi
+
+import (
+	"reflect"
+)
+
+// groupMap represents a persistent map from type to members of its group in registration order, see [trie]
+type groupMap struct {
+	groups trie[reflect.Type, []*provider]
+}
+
+// get returns members of group of type
+func (m groupMap) get(pType reflect.Type) []*provider {
+	members, _ := m.groups.get(hashString(pType.String()), pType)
+	return members
+}
+
+// with returns a copy of group map with provider added as a member of group of type, members are appended in place,
+// since state is changed only under lock starting from the latest state, so elements after the end of members slice
+// are never visible in other states
+func (m groupMap) with(pType reflect.Type, p *provider) groupMap {
+	return groupMap{groups: m.groups.with(hashString(pType.String()), pType, append(m.get(pType), p))}
+}
+
+// each calls function for each group in unspecified order
+func (m groupMap) each(function func(pType reflect.Type, members []*provider)) {
+	m.groups.each(function)
+}
+
+// groupMember represents one member of a group alongside with container that owns it
+type groupMember struct {
+	provider *provider
+	owner    *DI
+}
+
+// addGroupMember adds provider as a member of group of type
+func (s *state) addGroupMember(pType reflect.Type, p *provider) {
+	s.groups = s.groups.with(pType, p)
+}
+
+// groupMembers returns members of group of type from container and its parents, members of parents go first
+func (d *DI) groupMembers(pType reflect.Type) []groupMember {
+	var members []groupMember
+	if d.parent != nil {
+		members = d.parent.groupMembers(pType)
+	}
+
+	for _, p := range d.load().groups.get(pType) {
+		members = append(members, groupMember{provider: p, owner: d})
+	}
+
+	return members
+}
+
+// sliceGroupMembers returns members of group of element type if key is an unnamed slice
+func (d *DI) sliceGroupMembers(key providerKey) []groupMember {
+	if key.name != "" || key.pType.Kind() != reflect.Slice {
+		return nil
+	}
+	return d.groupMembers(key.pType.Elem())
+}
+
+// lookupGroup returns provider that assembles slice of all group members of element type if key is an unnamed slice
+// and group of its element type has members
+func (d *DI) lookupGroup(key providerKey) (*provider, *DI, bool) {
+	members := d.sliceGroupMembers(key)
+	if len(members) == 0 {
+		return nil, nil, false
+	}
+
+	return newGroupProvider(key.pType, members), d, true
+}
+
+// newGroupProvider creates provider that assembles slice of group members
+func newGroupProvider(sliceType reflect.Type, members []groupMember) *provider {
+	p := &provider{
+		disableCache: true,
+	}
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		result := reflect.MakeSlice(sliceType, 0, len(members))
+		for _, member := range members {
+			value, err := member.provider.provide(member.owner, res)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if member.provider.dedup != nil && containsEqual(result, value, member.provider.dedup) {
+				continue
+			}
+			result = reflect.Append(result, value)
+		}
+		return result, nil
+	}
+	return p
+}
+
+// containsEqual reports whether slice contains value equal to the value, see [WithDedup]
+func containsEqual(slice reflect.Value, value reflect.Value, equal func(a, b any) bool) bool {
+	for i := 0; i < slice.Len(); i++ {
+		if equal(slice.Index(i).Interface(), value.Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveGroup returns members of group of type T from container and its parents, members are returned in
+// registration order (members of parents go first), returns empty slice if group has no members
+func ResolveGroup[T any](d *DI) ([]T, error) {
+	key := providerKey{pType: typeOf[[]T]()}
+	p, owner, ok := d.lookupGroup(key)
+	if !ok {
+		return []T{}, nil
+	}
+
+	result, err := d.provideParam(key, p, owner, 0, newResolution(d))
+	if err != nil {
+		return nil, err
+	}
+	return result.Interface().([]T), nil
+}
+
+// MustResolveGroup is like [ResolveGroup], but panics if error occurs
+func MustResolveGroup[T any](d *DI) []T {
+	members, err := ResolveGroup[T](d)
+	if err != nil {
+		panic(err)
+	}
+	return members
+}
@@ -0,0 +1,173 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+type testPlugin interface {
+	Name() string
+}
+
+type testNamedPlugin string
+
+func (p testNamedPlugin) Name() string { return string(p) }
+
+func TestDI_ProvideWithGroup(t *testing.T) {
+	parent := New()
+	if err := ProvideValue[testPlugin](parent, testNamedPlugin("a"), WithGroup()); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := parent.ProvideAs((*testPlugin)(nil), testNamedPlugin("b"), WithGroup()); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	di := NewFrom(parent)
+	if err := di.ProvideAs((*testPlugin)(nil), func() testNamedPlugin { return "c" }, WithGroup()); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	names := func(plugins []testPlugin) string {
+		result := ""
+		for _, plugin := range plugins {
+			result += plugin.Name()
+		}
+		return result
+	}
+
+	di.MustInvoke(func(plugins []testPlugin) {
+		if names(plugins) != "abc" {
+			t.Fatalf("unexpected: %q", names(plugins))
+		}
+	})
+	di.MustInvoke(func(plugins ...testPlugin) {
+		if names(plugins) != "abc" {
+			t.Fatalf("unexpected: %q", names(plugins))
+		}
+	})
+	parent.MustInvoke(func(plugins []testPlugin) {
+		if names(plugins) != "ab" {
+			t.Fatalf("unexpected: %q", names(plugins))
+		}
+	})
+
+	if !HasType[[]testPlugin](di) || HasType[testPlugin](di) {
+		t.Fatalf("expected only slice of plugins to be provided")
+	}
+	if err := di.Validate(); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+}
+
+func TestDI_ProvideWithDedup(t *testing.T) {
+	t.Run("deep_equal", func(t *testing.T) {
+		di := New()
+		for i := 0; i < 2; i++ {
+			if err := ProvideValue[testPlugin](di, testNamedPlugin("a"), WithGroup(), WithDedup()); err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+		}
+		if err := ProvideValue[testPlugin](di, testNamedPlugin("b"), WithGroup(), WithDedup()); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if plugins := MustResolveGroup[testPlugin](di); len(plugins) != 2 {
+			t.Fatalf("unexpected: %d", len(plugins))
+		}
+	})
+
+	t.Run("custom_func", func(t *testing.T) {
+		sameName := func(a, b any) bool { return a.(testPlugin).Name() == b.(testPlugin).Name() }
+
+		di := New()
+		di.MustProvide(func() testPlugin { return testNamedPlugin("a") }, WithGroup(), WithDedupFunc(sameName))
+		di.MustProvide(func() testPlugin { return testNamedPlugin("a") }, WithGroup(), WithDedupFunc(sameName))
+
+		if plugins := MustResolveGroup[testPlugin](di); len(plugins) != 1 {
+			t.Fatalf("unexpected: %d", len(plugins))
+		}
+	})
+
+	t.Run("without_dedup", func(t *testing.T) {
+		di := New()
+		for i := 0; i < 2; i++ {
+			if err := ProvideValue[testPlugin](di, testNamedPlugin("a"), WithGroup()); err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+		}
+
+		if plugins := MustResolveGroup[testPlugin](di); len(plugins) != 2 {
+			t.Fatalf("unexpected: %d", len(plugins))
+		}
+	})
+}
+
+type testRegistry struct {
+	plugins []testPlugin
+}
+
+func TestDI_ProvideWithGroupConstructor(t *testing.T) {
+	di := New().MustProvide(func(plugins []testPlugin) *testRegistry {
+		return &testRegistry{plugins: plugins}
+	})
+	for _, name := range []string{"a", "b"} {
+		if err := ProvideValue[testPlugin](di, testNamedPlugin(name), WithGroup()); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	}
+	di.MustProvide(func() testPlugin { return testNamedPlugin("c") }, WithGroup())
+
+	if err := di.Validate(); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	registry := MustResolve[*testRegistry](di)
+	if len(registry.plugins) != 3 {
+		t.Fatalf("unexpected: %d", len(registry.plugins))
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		if registry.plugins[i].Name() != name {
+			t.Fatalf("unexpected: %q", registry.plugins[i].Name())
+		}
+	}
+}
+
+func TestResolveGroup(t *testing.T) {
+	t.Run("success_order", func(t *testing.T) {
+		di := New()
+		for _, name := range []string{"c", "a", "b"} {
+			if err := ProvideValue[testPlugin](di, testNamedPlugin(name), WithGroup()); err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			plugins := MustResolveGroup[testPlugin](di)
+			if len(plugins) != 3 || plugins[0].Name() != "c" || plugins[1].Name() != "a" || plugins[2].Name() != "b" {
+				t.Fatalf("unexpected: %v", plugins)
+			}
+		}
+	})
+
+	t.Run("success_empty", func(t *testing.T) {
+		plugins, err := ResolveGroup[testPlugin](New())
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if len(plugins) != 0 {
+			t.Fatalf("unexpected: %v", plugins)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideAs((*testPlugin)(nil), func() (testNamedPlugin, error) { return "", errTest },
+			WithGroup()); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if _, err := ResolveGroup[testPlugin](di); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+}
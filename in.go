@@ -0,0 +1,93 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// In is a marker that makes struct embedding it a parameter object, when function parameter is such struct, its
+// exported fields are resolved from the container instead of the struct itself, fields with tag `name:"<name>"` are
+// resolved by provider name and fields with tag `optional:"true"` are left unchanged if no provider found for them
+type In struct{}
+
+// inType represents type of [In]
+var inType = reflect.TypeOf(In{})
+
+// inField represents exported field of parameter object
+type inField struct {
+	index    int
+	name     string
+	key      providerKey
+	optional bool
+}
+
+// isIn reports whether type is a struct that embeds [In]
+func isIn(pType reflect.Type) bool {
+	return embedsMarker(pType, inType)
+}
+
+// embedsMarker reports whether type is a struct that embeds marker type
+func embedsMarker(pType reflect.Type, marker reflect.Type) bool {
+	if pType.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < pType.NumField(); i++ {
+		field := pType.Field(i)
+		if field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// inFields returns exported fields of parameter object that have to be resolved
+func inFields(pType reflect.Type) ([]inField, error) {
+	fields := make([]inField, 0, pType.NumField())
+	for i := 0; i < pType.NumField(); i++ {
+		field := pType.Field(i)
+		if !field.IsExported() || (field.Anonymous && field.Type == inType) {
+			continue
+		}
+
+		optional := false
+		if tag, ok := field.Tag.Lookup("optional"); ok {
+			var err error
+			optional, err = strconv.ParseBool(tag)
+			if err != nil {
+				return nil, fmt.Errorf("invalid optional tag %q of field %q: %w", tag, field.Name, err)
+			}
+		}
+
+		fields = append(fields, inField{
+			index:    i,
+			name:     field.Name,
+			key:      providerKey{pType: field.Type, name: field.Tag.Get("name")},
+			optional: optional,
+		})
+	}
+	return fields, nil
+}
+
+// invokeIn returns parameter object with fields resolved from the container
+func (d *DI) invokeIn(pType reflect.Type, i int, res *resolution) (reflect.Value, error) {
+	fields, err := inFields(pType)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("parameter object of type %q: %w", pType.String(), err)
+	}
+
+	value := reflect.New(pType).Elem()
+	for _, field := range fields {
+		if field.optional && !d.hasKey(field.key) {
+			continue
+		}
+
+		fieldValue, err := d.invokeParamKey(field.key, i, res)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("failed to provide field %q of %q: %w", field.name, pType.String(), err)
+		}
+		value.Field(field.index).Set(fieldValue)
+	}
+	return value, nil
+}
@@ -0,0 +1,66 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+type testParams struct {
+	In
+
+	Number   int
+	Text     string  `name:"primary"`
+	Optional float64 `optional:"true"`
+}
+
+func TestDI_In(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New().
+			MustProvide(1).
+			MustProvide("primary", WithName("primary")).
+			MustProvide("secondary")
+
+		di.MustInvoke(func(params testParams) {
+			if params.Number != 1 || params.Text != "primary" || params.Optional != 0 {
+				t.Fatalf("unexpected: %+v", params)
+			}
+		})
+
+		di.MustProvide(func(params testParams) bool { return params.Number == 1 })
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("success_optional", func(t *testing.T) {
+		di := New().
+			MustProvide(1).
+			MustProvide("primary", WithName("primary")).
+			MustProvide(2.0)
+
+		di.MustInvoke(func(params testParams) {
+			if params.Optional != 2.0 {
+				t.Fatalf("unexpected: %+v", params)
+			}
+		})
+	})
+
+	t.Run("error_missing", func(t *testing.T) {
+		err := New().MustProvide(1).MustProvide("secondary").Invoke(func(params testParams) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_tag", func(t *testing.T) {
+		type params struct {
+			In
+			Number int `optional:"maybe"`
+		}
+
+		err := New().MustProvide(1).Invoke(func(params params) {})
+		if err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ProviderInfo represents construction metadata of provider
+type ProviderInfo struct {
+	// Function reports whether provider is a function provider, otherwise it's a value provider
+	Function bool
+	// Cached reports whether value of provider is already constructed, value providers are always cached
+	Cached bool
+	// Eager reports whether provider uses eager loading
+	Eager bool
+	// MultiInstance reports whether provider constructs a new value on each resolution
+	MultiInstance bool
+	// RoundRobin reports whether provider uses round-robin over elements of provided value
+	RoundRobin bool
+	// Params are types of input parameters of function provider
+	Params []reflect.Type
+}
+
+// Describe returns construction metadata of provider of type from container or any of its parents, returns false if
+// there is no such provider, [Resolver] and [Provider] are described as value providers of container
+func (d *DI) Describe(pType reflect.Type) (ProviderInfo, bool) {
+	key := providerKey{pType: pType}
+	p, _, ok := d.lookupProvider(key)
+	if !ok {
+		if isContainerKey(key) {
+			return ProviderInfo{Cached: true}, true
+		}
+		return ProviderInfo{}, false
+	}
+
+	cache, function := p.getCacheOrFunction()
+	info := ProviderInfo{
+		Function:      function != nil,
+		Cached:        function == nil || cache.IsValid(),
+		Eager:         p.eagerLoading,
+		MultiInstance: p.disableCache,
+		RoundRobin:    p.useRoundRobin,
+	}
+	if function != nil {
+		fType := reflect.TypeOf(function)
+		info.Params = make([]reflect.Type, 0, fType.NumIn())
+		for i := 0; i < fType.NumIn(); i++ {
+			info.Params = append(info.Params, fType.In(i))
+		}
+	}
+	return info, true
+}
+
+// SnapshotEntry represents constructed value of provider
+type SnapshotEntry struct {
+	// Type is type name of provider
+	Type string
+	// Name is name of provider (empty if provider is unnamed)
+	Name string
+	// Value is string representation of value (formatted using %+v) or "[redacted]" if provider uses [WithRedact]
+	Value string
+}
+
+// Snapshot returns values of all constructed providers of container (without parents) sorted by type, providers that
+// weren't constructed yet and multi-instance providers are not included
+func (d *DI) Snapshot() []SnapshotEntry {
+	providers := d.providers()
+
+	var entries []SnapshotEntry
+	for _, key := range sortedKeys(providers) {
+		if key == diKey {
+			continue
+		}
+
+		p, _ := providers.get(key)
+		p.mutex.RLock()
+		value := p.cache
+		p.mutex.RUnlock()
+		if !value.IsValid() || p.disableCache {
+			continue
+		}
+
+		entry := SnapshotEntry{
+			Type: key.pType.String(),
+			Name: key.name,
+		}
+		if p.redact {
+			entry.Value = "[redacted]"
+		} else {
+			entry.Value = fmt.Sprintf("%+v", value.Interface())
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ProvidersByTag returns types of all providers of container (without parents) that have the tag sorted by type,
+// see [WithTags]
+func (d *DI) ProvidersByTag(tag string) []reflect.Type {
+	providers := d.providers()
+
+	var types []reflect.Type
+	seen := make(map[reflect.Type]bool)
+	for _, key := range sortedKeys(providers) {
+		p, _ := providers.get(key)
+		if seen[key.pType] || !slices.Contains(p.tags, tag) {
+			continue
+		}
+		seen[key.pType] = true
+		types = append(types, key.pType)
+	}
+	return types
+}
+
+// Len returns number of providers registered in container (without parents and container itself), group and keyed
+// members and collections of round-robin providers (see [WithRoundRobin]) are not counted
+func (d *DI) Len() int {
+	count := 0
+	d.providers().each(func(key providerKey, p *provider) {
+		if isCounted(key, p) {
+			count++
+		}
+	})
+	return count
+}
+
+// LenAll is like [DI.Len], but includes providers of parents, providers of the same type (and name) are counted once
+func (d *DI) LenAll() int {
+	keys := make(map[providerKey]struct{})
+	for di := d; di != nil; di = di.parent {
+		di.providers().each(func(key providerKey, p *provider) {
+			if isCounted(key, p) {
+				keys[key] = struct{}{}
+			}
+		})
+	}
+	return len(keys)
+}
+
+// isCounted reports whether provider of key is counted by [DI.Len]
+func isCounted(key providerKey, p *provider) bool {
+	return key != diKey && p.collectionOf == nil
+}
+
+// InstantiatedTypes returns types of function providers of container (without parents) that already constructed and
+// cached their values sorted by type, value providers and multi-instance providers are not included
+func (d *DI) InstantiatedTypes() []reflect.Type {
+	providers := d.providers()
+
+	var types []reflect.Type
+	seen := make(map[reflect.Type]bool)
+	for _, key := range sortedKeys(providers) {
+		p, _ := providers.get(key)
+		if seen[key.pType] || !p.isCached() {
+			continue
+		}
+		seen[key.pType] = true
+		types = append(types, key.pType)
+	}
+	return types
+}
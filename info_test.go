@@ -0,0 +1,164 @@
+package mdi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDI_Describe(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		di := New().MustProvide([]int{1, 2}, WithRoundRobin())
+
+		info, ok := di.Describe(typeOf[int]())
+		if !ok {
+			t.Fatalf("expected provider")
+		}
+		expected := ProviderInfo{Cached: true, RoundRobin: true}
+		if !reflect.DeepEqual(info, expected) {
+			t.Fatalf("expected info: %+v, but got: %+v", expected, info)
+		}
+	})
+
+	t.Run("function", func(t *testing.T) {
+		di := New().
+			MustProvide(1).
+			MustProvide(func(i int, _ *DI) string { return "" }).
+			MustProvide(func() float64 { return 0 }, WithMultiInstance()).
+			MustProvide(func() bool { return false }, WithEagerLoading())
+
+		info, ok := NewFrom(di).Describe(typeOf[string]())
+		if !ok {
+			t.Fatalf("expected provider")
+		}
+		expected := ProviderInfo{Function: true, Params: []reflect.Type{typeOf[int](), typeOf[*DI]()}}
+		if !reflect.DeepEqual(info, expected) {
+			t.Fatalf("expected info: %+v, but got: %+v", expected, info)
+		}
+
+		di.MustInvoke(func(s string) {})
+		if info, _ = di.Describe(typeOf[string]()); !info.Cached {
+			t.Fatalf("expected cached provider")
+		}
+
+		di.MustInvoke(func(f float64) {})
+		if info, _ = di.Describe(typeOf[float64]()); !info.MultiInstance || info.Cached {
+			t.Fatalf("unexpected: %+v", info)
+		}
+
+		if info, _ = di.Describe(typeOf[bool]()); !info.Eager || !info.Cached {
+			t.Fatalf("unexpected: %+v", info)
+		}
+	})
+
+	t.Run("container", func(t *testing.T) {
+		for _, pType := range []reflect.Type{typeOf[*DI](), typeOf[Resolver](), typeOf[Provider]()} {
+			info, ok := New().Describe(pType)
+			if !ok {
+				t.Fatalf("expected provider")
+			}
+			if expected := (ProviderInfo{Cached: true}); !reflect.DeepEqual(info, expected) {
+				t.Fatalf("expected info: %+v, but got: %+v", expected, info)
+			}
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		if _, ok := New().Describe(typeOf[int]()); ok {
+			t.Fatalf("unexpected provider")
+		}
+	})
+}
+
+func TestDI_Snapshot(t *testing.T) {
+	di := New().
+		MustProvide(1).
+		MustProvide("secret", WithName("password"), WithRedact()).
+		MustProvide(func(i int) float64 { return 2.5 }).
+		MustProvide(func() bool { return true }).
+		MustProvide(func() uint { return 1 }, WithMultiInstance())
+
+	di.MustInvoke(func(f float64, u uint) {})
+
+	expected := []SnapshotEntry{
+		{Type: "float64", Value: "2.5"},
+		{Type: "int", Value: "1"},
+		{Type: "string", Name: "password", Value: "[redacted]"},
+	}
+	if snapshot := di.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+		t.Fatalf("expected snapshot: %+v, but got: %+v", expected, snapshot)
+	}
+}
+
+func TestDI_ProvidersByTag(t *testing.T) {
+	di := New().
+		MustProvide(1, WithTags("http")).
+		MustProvide("a", WithTags("http", "public")).
+		MustProvide(func() bool { return true }, WithTags("grpc")).
+		MustProvide(2.5)
+
+	testCases := []struct {
+		name     string
+		tag      string
+		expected []reflect.Type
+	}{
+		{name: "http", tag: "http", expected: []reflect.Type{typeOf[int](), typeOf[string]()}},
+		{name: "grpc", tag: "grpc", expected: []reflect.Type{typeOf[bool]()}},
+		{name: "unknown", tag: "unknown", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if types := di.ProvidersByTag(tc.tag); !reflect.DeepEqual(types, tc.expected) {
+				t.Fatalf("expected types: %v, but got: %v", tc.expected, types)
+			}
+		})
+	}
+}
+
+func TestDI_Len(t *testing.T) {
+	parent := New().MustProvide(1).MustProvide("a")
+	di := parent.Scope().MustProvide(2).MustProvide(2.5).MustProvide(true, WithName("b"))
+
+	testCases := []struct {
+		name     string
+		len      int
+		expected int
+	}{
+		{name: "parent", len: parent.Len(), expected: 2},
+		{name: "parent_all", len: parent.LenAll(), expected: 2},
+		{name: "child", len: di.Len(), expected: 3},
+		{name: "child_all", len: di.LenAll(), expected: 4},
+		{name: "zero", len: (&DI{}).LenAll(), expected: 0},
+		{name: "round_robin", len: New().MustProvide([]int{1}, WithRoundRobin()).Len(), expected: 1},
+		{name: "round_robin_all", len: New().MustProvide([]int{1}, WithRoundRobin()).Scope().LenAll(), expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.len != tc.expected {
+				t.Fatalf("unexpected: %d", tc.len)
+			}
+		})
+	}
+}
+
+func TestDI_InstantiatedTypes(t *testing.T) {
+	di := New().
+		MustProvide(1).
+		MustProvide(func(i int) string { return "a" }).
+		MustProvide(func() float64 { return 2.5 }).
+		MustProvide(func() bool { return true }, WithMultiInstance()).
+		MustProvide(func() uint { return 1 })
+
+	if types := di.InstantiatedTypes(); len(types) != 0 {
+		t.Fatalf("unexpected types: %v", types)
+	}
+
+	di.MustInvoke(func(s string, b bool) {})
+	MustResolve[uint](di)
+
+	expected := []reflect.Type{typeOf[string](), typeOf[uint]()}
+	if types := di.InstantiatedTypes(); !reflect.DeepEqual(types, expected) {
+		t.Fatalf("expected types: %v, but got: %v", expected, types)
+	}
+}
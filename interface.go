@@ -0,0 +1,258 @@
+package mdi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ProvideAs adds provider to container under interface type, where iface is a nil pointer to interface
+// (e.g. (*io.Reader)(nil)), provided value or the first non-error return value of provided function must implement
+// that interface
+func (d *DI) ProvideAs(iface any, provide any, options ...ProviderOption) error {
+	iType, err := interfaceType(iface)
+	if err != nil {
+		return err
+	}
+
+	pValue := reflect.ValueOf(provide)
+	if pValue.Kind() == reflect.Func {
+		index, err := bindableOut(pValue.Type(), iType)
+		if err != nil {
+			return err
+		}
+		return d.provideFunctionValue(provide, iType, index, options)
+	}
+
+	if !pValue.IsValid() || !pValue.Type().Implements(iType) {
+		return newErrorNotImplements(reflect.TypeOf(provide), iType)
+	}
+	return d.provideValue(iType, pValue, options)
+}
+
+// ProvideAsMany adds provider to container under each of interface types, where ifaces are nil pointers to
+// interfaces, all interface types share the same provider, so the same instance will be resolved for each of them
+func (d *DI) ProvideAsMany(provide any, ifaces []any, options ...ProviderOption) error {
+	if len(ifaces) == 0 {
+		return fmt.Errorf("can't provide %T without interfaces", provide)
+	}
+	if pValue := reflect.ValueOf(provide); pValue.Kind() == reflect.Func && pValue.IsNil() {
+		return errors.New("can't provide nil function")
+	}
+
+	iTypes := make([]reflect.Type, 0, len(ifaces))
+	for _, iface := range ifaces {
+		iType, err := interfaceType(iface)
+		if err != nil {
+			return err
+		}
+		iTypes = append(iTypes, iType)
+	}
+
+	p := newProviderFromOptions(d.withDefaultOptions(options))
+	if p.useRoundRobin {
+		return newErrorProviderCantRoundRobin(iTypes[0])
+	}
+
+	var addTypes []reflect.Type
+	for _, iType := range iTypes {
+		ok, err := d.canAddProvider(iType, p)
+		if err != nil {
+			return err
+		}
+		if ok {
+			addTypes = append(addTypes, iType)
+		}
+	}
+	if len(addTypes) == 0 {
+		return nil
+	}
+
+	pValue := reflect.ValueOf(provide)
+	if pValue.Kind() == reflect.Func {
+		index := 0
+		for _, iType := range iTypes {
+			var err error
+			if index, err = bindableOut(pValue.Type(), iType); err != nil {
+				return err
+			}
+		}
+		p.setStrategyByFunctionValue(provide, index)
+	} else {
+		for _, iType := range iTypes {
+			if !pValue.IsValid() || !pValue.Type().Implements(iType) {
+				return newErrorNotImplements(reflect.TypeOf(provide), iType)
+			}
+		}
+		p.setStrategyByValue(pValue)
+	}
+
+	registrations := make([]registration, 0, len(addTypes))
+	for _, iType := range addTypes {
+		registrations = append(registrations, registration{pType: iType, provider: p})
+	}
+	if _, err := d.registerProviders(registrations); err != nil {
+		return err
+	}
+
+	if pValue.Kind() == reflect.Func {
+		return d.loadEagerly(addTypes[0], p)
+	}
+	return nil
+}
+
+// interfaceType returns interface type from pointer to interface
+func interfaceType(iface any) (reflect.Type, error) {
+	iType := reflect.TypeOf(iface)
+	if iType == nil || iType.Kind() != reflect.Ptr || iType.Elem().Kind() != reflect.Interface {
+		return nil, fmt.Errorf("can't use %v as interface, must be a pointer to interface", iType)
+	}
+	return iType.Elem(), nil
+}
+
+// missingMethods returns names of methods of interface that type doesn't have, methods with different signature are
+// marked as such
+func missingMethods(pType reflect.Type, iType reflect.Type) []string {
+	var missing []string
+	for i := 0; i < iType.NumMethod(); i++ {
+		method := iType.Method(i)
+		if pType == nil {
+			missing = append(missing, method.Name)
+			continue
+		}
+
+		pMethod, ok := pType.MethodByName(method.Name)
+		if !ok {
+			missing = append(missing, method.Name)
+			continue
+		}
+
+		mType := pMethod.Type
+		if pType.Kind() != reflect.Interface {
+			mType = methodWithoutReceiver(mType)
+		}
+		if mType != method.Type {
+			missing = append(missing, method.Name+" (wrong signature)")
+		}
+	}
+	return missing
+}
+
+// methodWithoutReceiver returns type of method with receiver removed from parameters
+func methodWithoutReceiver(mType reflect.Type) reflect.Type {
+	in := make([]reflect.Type, 0, mType.NumIn()-1)
+	for i := 1; i < mType.NumIn(); i++ {
+		in = append(in, mType.In(i))
+	}
+	out := make([]reflect.Type, 0, mType.NumOut())
+	for i := 0; i < mType.NumOut(); i++ {
+		out = append(out, mType.Out(i))
+	}
+	return reflect.FuncOf(in, out, mType.IsVariadic())
+}
+
+// bindableOut returns index of the first non-error return value of function if it implements interface
+func bindableOut(fType reflect.Type, iType reflect.Type) (int, error) {
+	for i := 0; i < fType.NumOut(); i++ {
+		out := fType.Out(i)
+		if isTypeErr(out) {
+			continue
+		}
+		if !out.Implements(iType) {
+			return 0, newErrorNotImplements(out, iType)
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("can't add func provider %q without return values", fType.String())
+}
+
+// EnableAssignableLookup makes container and its children resolve interface parameters that have no exact provider
+// by the only provider whose type is assignable to that interface, if there are several such providers an error
+// is returned
+func (d *DI) EnableAssignableLookup() {
+	_ = d.update(func(s *state) error {
+		s.assignable = true
+		return nil
+	})
+}
+
+// assignableLookup reports whether assignable lookup is enabled in container or any of its parents
+func (d *DI) assignableLookup() bool {
+	for di := d; di != nil; di = di.parent {
+		if di.load().assignable {
+			return true
+		}
+	}
+	return false
+}
+
+// assignableMatch represents the only provider assignable to interface alongside with states of containers it was
+// found in, match is valid only while states of these containers are unchanged
+type assignableMatch struct {
+	key      providerKey
+	provider *provider
+	owner    *DI
+	states   []*state
+}
+
+// lookupAssignable returns key and provider whose type is assignable to interface type of key, if assignable lookup
+// is enabled, returns nil provider if there are no such providers and an error if there are several of them, found
+// provider is remembered until container or any of its parents changes, so repeated lookups don't scan providers,
+// inactive providers are skipped and matches that depend on condition (see [WithConditional]) aren't remembered
+func (d *DI) lookupAssignable(i int, key providerKey) (providerKey, *provider, *DI, error) {
+	if key.pType.Kind() != reflect.Interface || !d.assignableLookup() {
+		return providerKey{}, nil, nil, nil
+	}
+
+	if value, ok := d.assignableMatches.Load(key); ok {
+		if m := value.(*assignableMatch); statesUnchanged(d, m.states) {
+			return m.key, m.provider, m.owner, nil
+		}
+	}
+
+	var (
+		matchKeys   []providerKey
+		matchKey    providerKey
+		match       *provider
+		matchOwner  *DI
+		overwritten = map[providerKey]bool{}
+		states      []*state
+		cacheable   = true
+	)
+	for di := d; di != nil; di = di.parent {
+		s := di.load()
+		states = append(states, s)
+		providers := s.provide
+		for _, pKey := range sortedKeys(providers) {
+			if overwritten[pKey] {
+				continue
+			}
+			if pKey.name != key.name || pKey.custom != key.custom || pKey.pType == key.pType ||
+				!pKey.pType.AssignableTo(key.pType) {
+				continue
+			}
+
+			p, _ := providers.get(pKey)
+			if p.condition != nil {
+				cacheable = false
+			}
+			if !p.isActive() {
+				continue
+			}
+			overwritten[pKey] = true
+
+			if match == nil {
+				match, matchKey, matchOwner = p, pKey, di
+			}
+			matchKeys = append(matchKeys, pKey)
+		}
+	}
+
+	if len(matchKeys) > 1 {
+		return providerKey{}, nil, nil, newErrorAmbiguousProvider(i, key, matchKeys)
+	}
+	if match != nil && cacheable {
+		d.assignableMatches.Store(key, &assignableMatch{key: matchKey, provider: match, owner: matchOwner, states: states})
+	}
+	return matchKey, match, matchOwner, nil
+}
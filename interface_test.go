@@ -0,0 +1,236 @@
+package mdi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testWrongReader struct{}
+
+func (testWrongReader) Read() error { return nil }
+
+func TestDI_ProvideAs(t *testing.T) {
+	t.Run("success_value", func(t *testing.T) {
+		di := New()
+		if err := di.ProvideAs((*io.Reader)(nil), os.Stdin); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		di.MustInvoke(func(r io.Reader) {
+			if r != os.Stdin {
+				t.Fatalf("unexpected: %v", r)
+			}
+		})
+		if HasType[*os.File](di) {
+			t.Fatalf("unexpected concrete type provider")
+		}
+	})
+
+	t.Run("success_func", func(t *testing.T) {
+		di := New()
+		buf := &bytes.Buffer{}
+		if err := di.ProvideAs((*io.Writer)(nil), func() (*bytes.Buffer, error) { return buf, nil }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		di.MustInvoke(func(w io.Writer) {
+			if w != buf {
+				t.Fatalf("unexpected: %v", w)
+			}
+		})
+	})
+
+	t.Run("error_not_implements", func(t *testing.T) {
+		err := New().ProvideAs((*io.Reader)(nil), 1)
+		if !errors.Is(err, ErrNotImplements) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotImplements, err)
+		}
+
+		err = New().ProvideAs((*io.Reader)(nil), func() int { return 1 })
+		if !errors.Is(err, ErrNotImplements) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotImplements, err)
+		}
+	})
+
+	t.Run("error_missing_methods", func(t *testing.T) {
+		err := New().ProvideAs((*io.ReadCloser)(nil), &bytes.Buffer{})
+		if !errors.Is(err, ErrNotImplements) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotImplements, err)
+		}
+		if !strings.Contains(err.Error(), "missing methods: Close") {
+			t.Fatalf("expected missing method in error, but got: %v", err)
+		}
+
+		err = New().ProvideAs((*io.Reader)(nil), testWrongReader{})
+		if err == nil || !strings.Contains(err.Error(), "missing methods: Read (wrong signature)") {
+			t.Fatalf("expected wrong signature in error, but got: %v", err)
+		}
+
+		err = New().ProvideAs((*io.Reader)(nil), nil)
+		if !errors.Is(err, ErrNotImplements) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotImplements, err)
+		}
+	})
+
+	t.Run("error_not_interface", func(t *testing.T) {
+		if err := New().ProvideAs(1, 1); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := New().ProvideAs((*int)(nil), 1); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
+
+type testWriteCloser struct {
+	bytes.Buffer
+}
+
+func (w *testWriteCloser) Close() error { return nil }
+
+func TestDI_ProvideAsMany(t *testing.T) {
+	t.Run("success_func", func(t *testing.T) {
+		calls := 0
+		di := New()
+		err := di.ProvideAsMany(func() *testWriteCloser {
+			calls++
+			return &testWriteCloser{}
+		}, []any{(*io.Writer)(nil), (*io.Closer)(nil)})
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(w io.Writer, c io.Closer) {
+			if w.(*testWriteCloser) != c.(*testWriteCloser) {
+				t.Fatalf("expected identical instances")
+			}
+		})
+		if calls != 1 {
+			t.Fatalf("expected one call, but got: %d", calls)
+		}
+	})
+
+	t.Run("success_value", func(t *testing.T) {
+		di := New()
+		value := &testWriteCloser{}
+		if err := di.ProvideAsMany(value, []any{(*io.Writer)(nil), (*io.Closer)(nil)}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		di.MustInvoke(func(w io.Writer, c io.Closer) {
+			if w != io.Writer(value) || c != io.Closer(value) {
+				t.Fatalf("expected identical instances")
+			}
+		})
+	})
+
+	t.Run("error_not_implements", func(t *testing.T) {
+		di := New()
+		err := di.ProvideAsMany(func() *bytes.Buffer { return nil }, []any{(*io.Writer)(nil), (*io.Closer)(nil)})
+		if !errors.Is(err, ErrNotImplements) {
+			t.Fatalf("expected error: %q, but got: %v", ErrNotImplements, err)
+		}
+		if HasType[io.Writer](di) {
+			t.Fatalf("unexpected partial registration")
+		}
+	})
+}
+
+func TestDI_EnableAssignableLookup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		parent := New().MustProvide(buf)
+		parent.EnableAssignableLookup()
+		di := NewFrom(parent).MustProvide(1)
+
+		di.MustInvoke(func(w io.Writer, i int) {
+			if w != buf {
+				t.Fatalf("unexpected: %v", w)
+			}
+		})
+	})
+
+	t.Run("success_exact_first", func(t *testing.T) {
+		di := New().MustProvide(&bytes.Buffer{})
+		di.EnableAssignableLookup()
+		if err := di.ProvideAs((*io.Writer)(nil), os.Stdout); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		di.MustInvoke(func(w io.Writer) {
+			if w != os.Stdout {
+				t.Fatalf("unexpected: %v", w)
+			}
+		})
+	})
+
+	t.Run("conditional", func(t *testing.T) {
+		var enabled bool
+		di := New().MustProvide(&bytes.Buffer{}, WithConditional(func() bool { return enabled }))
+		di.EnableAssignableLookup()
+
+		if err := di.Invoke(func(w io.Writer) {}); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		enabled = true
+		if err := di.Invoke(func(w io.Writer) {}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		enabled = false
+		if err := di.Invoke(func(w io.Writer) {}); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_disabled", func(t *testing.T) {
+		err := New().MustProvide(&bytes.Buffer{}).Invoke(func(w io.Writer) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_ambiguous", func(t *testing.T) {
+		di := New().MustProvide(&bytes.Buffer{}).MustProvide(&testWriteCloser{})
+		di.EnableAssignableLookup()
+
+		err := di.Invoke(func(w io.Writer) {})
+		if !errors.Is(err, ErrAmbiguousProvider) {
+			t.Fatalf("expected error: %q, but got: %v", ErrAmbiguousProvider, err)
+		}
+		if _, err = di.ResolvePlan(func(w io.Writer) {}); !errors.Is(err, ErrAmbiguousProvider) {
+			t.Fatalf("expected error: %q, but got: %v", ErrAmbiguousProvider, err)
+		}
+	})
+}
+
+func TestDI_AssignableLookupCache(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parent := New().MustProvide(buf)
+	parent.EnableAssignableLookup()
+	di := parent.Scope()
+
+	// Remembered match is resolved as cheaply as provider of concrete type, while scan allocates on each lookup
+	assertCached := func() {
+		t.Helper()
+		lookup := testing.AllocsPerRun(10, func() {
+			if w := MustResolve[io.Writer](di); w != buf {
+				t.Fatalf("unexpected: %v", w)
+			}
+		})
+		direct := testing.AllocsPerRun(10, func() { _ = MustResolve[*bytes.Buffer](di) })
+		if lookup != direct {
+			t.Fatalf("unexpected: %v", lookup)
+		}
+	}
+
+	assertCached()
+
+	di.MustProvide(1)
+	assertCached()
+
+	parent.MustProvide(&strings.Builder{})
+	if _, err := Resolve[io.Writer](di); !errors.Is(err, ErrAmbiguousProvider) {
+		t.Fatalf("expected error: %q, but got: %v", ErrAmbiguousProvider, err)
+	}
+}
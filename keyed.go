@@ -0,0 +1,91 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// addKeyedMember adds provider to keyed collection of type, returns error if provider with the same key already exists
+func (s *state) addKeyedMember(pType reflect.Type, p *provider) error {
+	if err := checkKeyedMember(s, pType, p); err != nil {
+		return err
+	}
+	s.keyed = s.keyed.with(pType, p)
+	return nil
+}
+
+// canAddKeyedMember checks if provider can be added to keyed collection of type
+func (d *DI) canAddKeyedMember(pType reflect.Type, p *provider) error {
+	return checkKeyedMember(d.load(), pType, p)
+}
+
+// checkKeyedMember checks that key of provider is valid and not used in keyed collection of type
+func checkKeyedMember(s *state, pType reflect.Type, p *provider) error {
+	if !reflect.TypeOf(p.mapKey).Comparable() {
+		return fmt.Errorf("can't use key of type %T, must be comparable", p.mapKey)
+	}
+	for _, member := range s.keyed.get(pType) {
+		if member.mapKey == p.mapKey {
+			return &wrappedError{
+				message: fmt.Sprintf("provider of type %q with key %v already exists", pType.String(), p.mapKey),
+				err:     ErrProviderAlreadyExists,
+			}
+		}
+	}
+	return nil
+}
+
+// keyedMembers returns members of keyed collection of type from container and its parents, members of container
+// override members of parents with the same key
+func (d *DI) keyedMembers(pType reflect.Type) []groupMember {
+	var members []groupMember
+	if d.parent != nil {
+		members = d.parent.keyedMembers(pType)
+	}
+
+	for _, p := range d.load().keyed.get(pType) {
+		overridden := false
+		for i, member := range members {
+			if member.provider.mapKey == p.mapKey {
+				members[i] = groupMember{provider: p, owner: d}
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			members = append(members, groupMember{provider: p, owner: d})
+		}
+	}
+
+	return members
+}
+
+// ResolveMap returns map of all providers of type V added with [WithKey] option, all keys must be of type K
+func ResolveMap[K comparable, V any](d *DI) (map[K]V, error) {
+	kType, vType := typeOf[K](), typeOf[V]()
+	res, err := newResolution(d).push(providerKey{pType: reflect.MapOf(kType, vType)})
+	if err != nil {
+		return nil, err
+	}
+
+	members := d.keyedMembers(vType)
+	result := make(map[K]V, len(members))
+	for _, member := range members {
+		key, ok := member.provider.mapKey.(K)
+		if !ok {
+			return nil, fmt.Errorf("can't use key %v of type %T as %q", member.provider.mapKey,
+				member.provider.mapKey, kType.String())
+		}
+
+		value, err := member.provider.provide(member.owner, res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provide value of type %q with key %v: %w", vType.String(), key, err)
+		}
+
+		var v V
+		reflect.ValueOf(&v).Elem().Set(value)
+		result[key] = v
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+type testCommand interface {
+	Run() string
+}
+
+type testEchoCommand string
+
+func (c testEchoCommand) Run() string { return string(c) }
+
+func TestResolveMap(t *testing.T) {
+	parent := New()
+	if err := ProvideValue[testCommand](parent, testEchoCommand("start"), WithKey("start")); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideValue[testCommand](parent, testEchoCommand("old"), WithKey("stop")); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	di := NewFrom(parent)
+	if err := di.ProvideAs((*testCommand)(nil), func() testEchoCommand { return "stop" }, WithKey("stop")); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideValue[testCommand](di, testEchoCommand("help"), WithKey("help")); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	commands, err := ResolveMap[string, testCommand](di)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if len(commands) != 3 {
+		t.Fatalf("unexpected commands: %v", commands)
+	}
+	for key, command := range commands {
+		if command.Run() != key {
+			t.Fatalf("unexpected command %q: %q", key, command.Run())
+		}
+	}
+
+	if HasType[testCommand](di) {
+		t.Fatalf("unexpected unique provider")
+	}
+
+	err = ProvideValue[testCommand](di, testEchoCommand("help"), WithKey("help"))
+	if !errors.Is(err, ErrProviderAlreadyExists) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+	}
+
+	if _, err = ResolveMap[int, testCommand](di); err == nil {
+		t.Fatalf("expected key type error, but got nil")
+	}
+}
@@ -0,0 +1,77 @@
+package mdi
+
+// parentLink represents provider found in parents of container alongside with states of parents it was found in,
+// link is valid only while states of these parents are unchanged
+type parentLink struct {
+	provider *provider
+	owner    *DI
+	states   []*state
+}
+
+// EnableParentLinkCache makes container remember which parent owns provider of each resolved key, so subsequent
+// lookups don't search parents again, remembered link is dropped once any of the searched parents changes, providers
+// with condition (see [WithConditional]) are never remembered
+func (d *DI) EnableParentLinkCache() {
+	_ = d.update(func(s *state) error {
+		s.linkCache = true
+		return nil
+	})
+}
+
+// lookupParentLink returns provider by key from parents of container using remembered links if possible, see
+// [DI.EnableParentLinkCache]
+func (d *DI) lookupParentLink(key providerKey) (*provider, *DI, bool) {
+	if value, ok := d.parentLinks.Load(key); ok {
+		if link := value.(*parentLink); statesUnchanged(d.parent, link.states) {
+			return link.provider, link.owner, true
+		}
+	}
+
+	// Default provider yields to non-default provider of parents further up, the same as in [DI.lookupProvider]
+	var found *parentLink
+	cacheable := true
+	var states []*state
+	for di := d.parent; di != nil; di = di.parent {
+		s := di.load()
+		states = append(states, s)
+
+		p, ok := s.provide.get(key)
+		if !ok {
+			continue
+		}
+		if p.condition != nil {
+			cacheable = false
+		}
+		if !p.isActive() {
+			continue
+		}
+
+		if found == nil || !p.isDefault {
+			found = &parentLink{provider: p, owner: di}
+		}
+		if !p.isDefault {
+			break
+		}
+	}
+	if found == nil {
+		return nil, nil, false
+	}
+
+	if cacheable {
+		found.states = states
+		d.parentLinks.Store(key, found)
+	}
+	return found.provider, found.owner, true
+}
+
+// statesUnchanged reports whether states of container and its parents are the same as remembered states
+func statesUnchanged(d *DI, states []*state) bool {
+	di := d
+	for _, s := range states {
+		if di == nil || di.load() != s {
+			return false
+		}
+		di = di.parent
+	}
+	return true
+}
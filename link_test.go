@@ -0,0 +1,87 @@
+package mdi
+
+import "testing"
+
+func TestDI_EnableParentLinkCache(t *testing.T) {
+	root := New().MustProvide(1)
+	parent := root.Scope()
+	di := parent.Scope()
+	di.EnableParentLinkCache()
+
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	if _, ok := di.parentLinks.Load(providerKey{pType: typeOf[int]()}); !ok {
+		t.Fatal("expected link to be remembered")
+	}
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	parent.MustProvide(2)
+	if value := MustResolve[int](di); value != 2 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	di.MustProvide(3)
+	if value := MustResolve[int](di); value != 3 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	var enabled bool
+	root.MustProvide("a", WithConditional(func() bool { return enabled }))
+	if HasType[string](di) {
+		t.Fatal("unexpected inactive provider")
+	}
+	enabled = true
+	if !HasType[string](di) {
+		t.Fatal("expected active provider")
+	}
+	if _, ok := di.parentLinks.Load(providerKey{pType: typeOf[string]()}); ok {
+		t.Fatal("unexpected link of conditional provider")
+	}
+}
+
+func BenchmarkDI_ParentLinkCache(b *testing.B) {
+	newDeepScope := func() *DI {
+		di := New().MustProvide(1)
+		for i := 0; i < 16; i++ {
+			di = di.Scope()
+		}
+		return di
+	}
+
+	b.Run("disabled", func(b *testing.B) {
+		di := newDeepScope()
+		for i := 0; i < b.N; i++ {
+			_ = MustResolve[int](di)
+		}
+	})
+
+	b.Run("enabled", func(b *testing.B) {
+		di := newDeepScope()
+		di.EnableParentLinkCache()
+		for i := 0; i < b.N; i++ {
+			_ = MustResolve[int](di)
+		}
+	})
+}
+
+func TestDI_EnableParentLinkCacheDefault(t *testing.T) {
+	root := New().MustProvide(1)
+	parent := root.Scope()
+	if err := parent.ProvideDefault(2); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	di := parent.Scope()
+
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	di.EnableParentLinkCache()
+	for i := 0; i < 2; i++ {
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package mdi
+
+import "fmt"
+
+// Module represents reusable bundle of providers and functions to invoke, see [DI.Use]
+type Module struct {
+	name     string
+	provides []any
+	invokes  []any
+}
+
+// NewModule creates new module with name, providers and functions to invoke
+func NewModule(name string, provides []any, invokes []any) Module {
+	return Module{
+		name:     name,
+		provides: provides,
+		invokes:  invokes,
+	}
+}
+
+// Name returns name of module
+func (m Module) Name() string {
+	return m.name
+}
+
+// Use applies modules to container, providers of all modules are added first, so modules can depend on each other,
+// then functions of each module are invoked in order of modules, errors are prefixed with name of module
+func (d *DI) Use(modules ...Module) error {
+	for _, module := range modules {
+		if err := d.ProvideAll(module.provides...); err != nil {
+			return fmt.Errorf("module %q: %w", module.name, err)
+		}
+	}
+
+	for _, module := range modules {
+		if err := d.Invoke(module.invokes...); err != nil {
+			return fmt.Errorf("module %q: %w", module.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package mdi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDI_Use(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var result string
+		config := NewModule("config", []any{1}, nil)
+		server := NewModule("server", []any{func(port int) string { return ":" + strconv.Itoa(port) }}, []any{
+			func(addr string) { result = addr },
+		})
+
+		if config.Name() != "config" {
+			t.Fatalf("unexpected: %q", config.Name())
+		}
+
+		di := New()
+		if err := di.Use(server, config); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if result != ":1" {
+			t.Fatalf("unexpected: %q", result)
+		}
+	})
+
+	t.Run("error_provide", func(t *testing.T) {
+		err := New().Use(NewModule("a", []any{1}, nil), NewModule("b", []any{2}, nil))
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if !strings.HasPrefix(err.Error(), `module "b": `) {
+			t.Fatalf("expected module name in error, but got: %v", err)
+		}
+	})
+
+	t.Run("error_invoke", func(t *testing.T) {
+		err := New().Use(NewModule("a", nil, []any{func(int) {}}))
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if !strings.HasPrefix(err.Error(), `module "a": `) {
+			t.Fatalf("expected module name in error, but got: %v", err)
+		}
+	})
+}
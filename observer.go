@@ -0,0 +1,24 @@
+package mdi
+
+import (
+	"reflect"
+	"time"
+)
+
+// Observer represents observer of dependency resolutions
+type Observer interface {
+	// OnResolveStart is called before dependency of type is resolved
+	OnResolveStart(pType reflect.Type)
+
+	// OnResolveEnd is called after dependency of type is resolved with resolution error (if any) and its duration
+	OnResolveEnd(pType reflect.Type, err error, duration time.Duration)
+}
+
+// SetObserver sets observer of dependency resolutions (nil to remove observer), observer is inherited by child
+// containers that don't have their own observer
+func (d *DI) SetObserver(observer Observer) {
+	_ = d.update(func(s *state) error {
+		s.observer = observer
+		return nil
+	})
+}
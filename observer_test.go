@@ -0,0 +1,61 @@
+package mdi
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testObserver struct {
+	events []string
+	mutex  sync.Mutex
+}
+
+func (o *testObserver) OnResolveStart(pType reflect.Type) {
+	o.mutex.Lock()
+	o.events = append(o.events, "start "+pType.String())
+	o.mutex.Unlock()
+}
+
+func (o *testObserver) OnResolveEnd(pType reflect.Type, err error, _ time.Duration) {
+	o.mutex.Lock()
+	if err != nil {
+		o.events = append(o.events, "error "+pType.String())
+	} else {
+		o.events = append(o.events, "end "+pType.String())
+	}
+	o.mutex.Unlock()
+}
+
+func TestDI_SetObserver(t *testing.T) {
+	observer := &testObserver{}
+
+	parent := New()
+	parent.SetObserver(observer)
+
+	di := NewFrom(parent).
+		MustProvide(1).
+		MustProvide(func(i int) string { return "" }).
+		MustProvide(func(i int) (float64, error) { return 0, errTest })
+
+	di.MustInvoke(func(s string) {})
+	if err := di.Invoke(func(f float64) {}); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+
+	expected := []string{
+		"start string", "start int", "end int", "end string",
+		"start float64", "start int", "end int", "error float64",
+	}
+	if !reflect.DeepEqual(observer.events, expected) {
+		t.Fatalf("unexpected events: %v", observer.events)
+	}
+
+	observer.events = nil
+	parent.SetObserver(nil)
+	di.MustInvoke(func(s string) {})
+	if len(observer.events) != 0 {
+		t.Fatalf("unexpected events: %v", observer.events)
+	}
+}
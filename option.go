@@ -0,0 +1,21 @@
+package mdi
+
+// Option represents container options
+type Option func(s *state)
+
+// WithAllowOverride container's option to replace existing provider of the same type (and name) instead of returning
+// an error, replaced provider is dropped together with its cached value, priorities still apply, so provider with
+// lower priority is ignored (see [WithPriority])
+func WithAllowOverride() Option {
+	return func(s *state) {
+		s.allowOverride = true
+	}
+}
+
+// WithRecover container's option to recover panics of constructors, decorators and invoked functions and return them
+// as errors, it's inherited by child containers, it's disabled by default to not hide bugs
+func WithRecover() Option {
+	return func(s *state) {
+		s.recoverPanics = true
+	}
+}
@@ -0,0 +1,52 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithAllowOverride(t *testing.T) {
+	di := New(WithAllowOverride())
+	di.MustProvide(1)
+	if value := MustResolve[int](di); value != 1 {
+		t.Fatalf("unexpected: %d", value)
+	}
+	di.MustProvide(2)
+	if value := MustResolve[int](di); value != 2 {
+		t.Fatalf("unexpected: %d", value)
+	}
+
+	di.MustProvide(func() string { return "first" })
+	if value := MustResolve[string](di); value != "first" {
+		t.Fatalf("unexpected: %q", value)
+	}
+	di.MustProvide(func() string { return "second" })
+	if value := MustResolve[string](di); value != "second" {
+		t.Fatalf("unexpected: %q", value)
+	}
+
+	if err := NewFrom(di).MustProvide(1).Provide(2); !errors.Is(err, ErrProviderAlreadyExists) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+	}
+}
+
+func TestWithRecover(t *testing.T) {
+	di := NewFrom(New(WithRecover())).MustProvide(func() int { panic("constructor") })
+
+	err := di.Invoke(func(i int) {})
+	if !errors.Is(err, ErrPanicked) {
+		t.Fatalf("expected error: %q, but got: %v", ErrPanicked, err)
+	}
+	t.Logf("invoke error: %q", err)
+
+	if err = di.Invoke(func() { panic("invoke") }); !errors.Is(err, ErrPanicked) {
+		t.Fatalf("expected error: %q, but got: %v", ErrPanicked, err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	_ = New().Invoke(func() { panic("no recover") })
+}
@@ -0,0 +1,106 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Out is a marker that makes struct embedding it a result object, when provided function returns such struct, each
+// of its exported fields is registered as a separate provider, fields with tag `name:"<name>"` are registered with
+// provider name and fields with tag `group:"true"` are added to the group of their type, result object itself is
+// registered as well and constructed only once for all fields
+type Out struct{}
+
+// outType represents type of [Out]
+var outType = reflect.TypeOf(Out{})
+
+// outField represents exported field of result object
+type outField struct {
+	index int
+	pType reflect.Type
+	name  string
+	group bool
+}
+
+// isOut reports whether type is a struct that embeds [Out]
+func isOut(pType reflect.Type) bool {
+	return embedsMarker(pType, outType)
+}
+
+// outFields returns exported fields of result object that have to be registered
+func outFields(pType reflect.Type) ([]outField, error) {
+	fields := make([]outField, 0, pType.NumField())
+	for i := 0; i < pType.NumField(); i++ {
+		field := pType.Field(i)
+		if !field.IsExported() || (field.Anonymous && field.Type == outType) {
+			continue
+		}
+
+		group := false
+		if tag, ok := field.Tag.Lookup("group"); ok {
+			var err error
+			group, err = strconv.ParseBool(tag)
+			if err != nil {
+				return nil, fmt.Errorf("invalid group tag %q of field %q: %w", tag, field.Name, err)
+			}
+		}
+
+		fields = append(fields, outField{
+			index: i,
+			pType: field.Type,
+			name:  field.Tag.Get("name"),
+			group: group,
+		})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("result object of type %q has no exported fields", pType.String())
+	}
+	return fields, nil
+}
+
+// outRegistrations returns provider of result object returned by function and providers of each of its fields that
+// extract them from the result object
+func outRegistrations(function any, pType reflect.Type, index int, options []ProviderOption) ([]registration, error) {
+	fields, err := outFields(pType)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newProviderFromOptions(options)
+	if p.useRoundRobin {
+		return nil, newErrorProviderCantRoundRobin(pType)
+	}
+	if p.name != "" || p.group || p.mapKey != nil {
+		return nil, fmt.Errorf("can't use name, group or key options for result object of type %q, use field tags "+
+			"instead", pType.String())
+	}
+
+	registrations := make([]registration, 0, len(fields)+1)
+	registrations = append(registrations, registration{
+		pType:    pType,
+		provider: p.setStrategyByFunctionValue(function, index),
+	})
+	for _, field := range fields {
+		fieldProvider := &provider{
+			name:         field.name,
+			group:        field.group,
+			priority:     p.priority,
+			disableCache: p.disableCache,
+		}
+		registrations = append(registrations, registration{
+			pType:    field.pType,
+			provider: fieldProvider.setStrategyByFunctionValue(outFieldFunction(pType, field), 0),
+		})
+	}
+	return registrations, nil
+}
+
+// outFieldFunction returns function that extracts field from result object
+func outFieldFunction(pType reflect.Type, field outField) any {
+	fType := reflect.FuncOf([]reflect.Type{pType}, []reflect.Type{field.pType}, false)
+	return reflect.MakeFunc(fType, func(args []reflect.Value) []reflect.Value {
+		return []reflect.Value{args[0].Field(field.index)}
+	}).Interface()
+}
@@ -0,0 +1,78 @@
+package mdi
+
+import (
+	"errors"
+	"testing"
+)
+
+type testResults struct {
+	Out
+
+	Number int
+	Text   string `name:"primary"`
+	Plugin string `group:"true"`
+}
+
+func TestDI_Out(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		called := 0
+		di := New().MustProvide(func() (testResults, error) {
+			called++
+			return testResults{Number: 1, Text: "primary", Plugin: "plugin"}, nil
+		})
+
+		di.MustInvoke(func(i int) {
+			if i != 1 {
+				t.Fatalf("unexpected: %d", i)
+			}
+		})
+		if err := di.Invoke(func(s string) {}); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+
+		type params struct {
+			In
+			Text    string `name:"primary"`
+			Plugins []string
+		}
+		di.MustInvoke(func(p params) {
+			if p.Text != "primary" || len(p.Plugins) != 1 || p.Plugins[0] != "plugin" {
+				t.Fatalf("unexpected: %+v", p)
+			}
+		})
+
+		if called != 1 {
+			t.Fatalf("unexpected: %d", called)
+		}
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("error_constructor", func(t *testing.T) {
+		di := New().MustProvide(func() (testResults, error) { return testResults{}, errTest })
+
+		if err := di.Invoke(func(i int) {}); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+
+	t.Run("error_exists", func(t *testing.T) {
+		di := New().MustProvide(2)
+
+		err := di.Provide(func() testResults { return testResults{} })
+		if !errors.Is(err, ErrProviderAlreadyExists) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+		}
+		if HasType[testResults](di) {
+			t.Fatalf("unexpected result object provider")
+		}
+	})
+
+	t.Run("error_options", func(t *testing.T) {
+		err := New().Provide(func() testResults { return testResults{} }, WithName("results"))
+		if err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
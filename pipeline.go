@@ -0,0 +1,31 @@
+package mdi
+
+// Pipeline calls functions in order, return values of each function are provided to all following functions,
+// providers are registered in temporary child container and discarded after pipeline completes, so the container
+// itself is not changed, values returned later override earlier values of the same type, if any function returns
+// an error, pipeline is aborted and the error is returned
+func (d *DI) Pipeline(functions ...any) error {
+	scope := NewFrom(d, WithAllowOverride())
+	for _, function := range functions {
+		results, err := scope.invoke(function, newResolution(scope))
+		if err != nil {
+			return err
+		}
+
+		registrations := make([]registration, 0, len(results))
+		for _, result := range results {
+			if isTypeErr(result.Type()) {
+				continue
+			}
+			registrations = append(registrations, registration{
+				pType:    result.Type(),
+				provider: newProviderFromOptions(nil).setStrategyByValue(result),
+			})
+		}
+
+		if _, err = scope.registerProviders(registrations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package mdi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDI_Pipeline(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		di := New()
+
+		var result string
+		err := di.Pipeline(
+			func() int { return 1 },
+			func(i int) (float64, error) { return float64(i) + 0.5, nil },
+			func(i int, f float64) { result = fmt.Sprint(i, " ", f) },
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if result != "1 1.5" {
+			t.Fatalf("unexpected: %q", result)
+		}
+
+		if di.Has(typeOf[int]()) || di.Has(typeOf[float64]()) {
+			t.Fatal("unexpected temporary providers")
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		var result int
+		err := New().Pipeline(
+			func() int { return 1 },
+			func(i int) int { return i + 1 },
+			func(i int) { result = i },
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if result != 2 {
+			t.Fatalf("unexpected: %d", result)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		called := false
+		err := New().Pipeline(
+			func() (int, error) { return 0, errTest },
+			func() { called = true },
+		)
+		if !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+		if called {
+			t.Fatal("unexpected call after error")
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		err := New().Pipeline(func() int { return 1 }, func(string) {})
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Populate sets exported fields of struct with dependencies from the container, target must be a non-nil pointer to
+// struct, fields with tag `di:"-"` are skipped, fields with tag `di:"optional"` are left unchanged if no provider
+// found for them and fields with tag `di:"name=<name>"` are resolved by provider name, options can be combined using
+// comma (e.g. `di:"optional,name=primary"`)
+func (d *DI) Populate(target any) error {
+	tValue := reflect.ValueOf(target)
+	if tValue.Kind() != reflect.Ptr || tValue.IsNil() || tValue.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can't populate %T, must be a non-nil pointer to struct", target)
+	}
+
+	sValue := tValue.Elem()
+	sType := sValue.Type()
+	res := newResolution(d)
+	for i := 0; i < sType.NumField(); i++ {
+		field := sType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("di")
+		if ok && tag == "-" {
+			continue
+		}
+
+		key, optional, err := parseFieldTag(field, tag)
+		if err != nil {
+			return err
+		}
+		if optional && !d.hasKey(key) {
+			continue
+		}
+
+		value, err := d.invokeParamKey(key, i, res)
+		if err != nil {
+			return fmt.Errorf("failed to populate field %q: %w", field.Name, err)
+		}
+		sValue.Field(i).Set(value)
+	}
+
+	return nil
+}
+
+// parseFieldTag parses `di` tag of struct field and returns key of provider for it and if it's optional
+func parseFieldTag(field reflect.StructField, tag string) (providerKey, bool, error) {
+	key := providerKey{pType: field.Type}
+	optional := false
+	if tag == "" {
+		return key, optional, nil
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(option), "=")
+		switch {
+		case name == "optional" && !hasValue:
+			optional = true
+		case name == "name" && hasValue:
+			key.name = value
+		default:
+			return key, false, fmt.Errorf("unknown tag option %q of field %q", option, field.Name)
+		}
+	}
+
+	return key, optional, nil
+}
+
+// Fill sets value that target points to with dependency of its type from the container (e.g. var w io.Writer;
+// d.Fill(&w)), target must be a non-nil pointer
+func (d *DI) Fill(target any) error {
+	tValue := reflect.ValueOf(target)
+	if tValue.Kind() != reflect.Ptr || tValue.IsNil() {
+		return fmt.Errorf("can't fill %T, must be a non-nil pointer", target)
+	}
+
+	value, err := d.invokeParam(tValue.Type().Elem(), 0, newResolution(d))
+	if err != nil {
+		return err
+	}
+
+	tValue.Elem().Set(value)
+	return nil
+}
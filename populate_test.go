@@ -0,0 +1,165 @@
+package mdi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDI_Populate(t *testing.T) {
+	di := New().MustProvide(1).MustProvide(func(i int) string { return "test" })
+
+	t.Run("success", func(t *testing.T) {
+		target := struct {
+			Int      int
+			Str      string
+			Optional float64 `di:"optional"`
+			Skipped  int     `di:"-"`
+			private  int
+		}{
+			Optional: 2,
+		}
+
+		if err := di.Populate(&target); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		if target.Int != 1 || target.Str != "test" {
+			t.Fatalf("unexpected: %d %q", target.Int, target.Str)
+		}
+		if target.Optional != 2 || target.Skipped != 0 || target.private != 0 {
+			t.Fatalf("unexpected: %f %d %d", target.Optional, target.Skipped, target.private)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		target := struct {
+			Int   int
+			Float float64
+		}{}
+
+		err := di.Populate(&target)
+		if !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("optional_group", func(t *testing.T) {
+		groupDI := New().MustProvide(1, WithGroup()).MustProvide(2, WithGroup())
+		target := struct {
+			Ints []int `di:"optional"`
+		}{}
+
+		if err := groupDI.Populate(&target); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if len(target.Ints) != 2 {
+			t.Fatalf("unexpected: %v", target.Ints)
+		}
+	})
+
+	t.Run("error_not_struct_pointer", func(t *testing.T) {
+		if err := di.Populate(struct{}{}); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		i := 0
+		if err := di.Populate(&i); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+		if err := di.Populate((*struct{})(nil)); err == nil {
+			t.Fatalf("expected error, but got nil")
+		}
+	})
+}
+
+func TestDI_PopulateNamed(t *testing.T) {
+	di := New().
+		MustProvide(1).
+		MustProvide(2, WithName("primary")).
+		MustProvide(func() int { return 3 }, WithName("replica"))
+
+	t.Run("success", func(t *testing.T) {
+		target := struct {
+			Int      int
+			Primary  int `di:"name=primary"`
+			Replica  int `di:"name=replica"`
+			Optional int `di:"optional,name=unknown"`
+		}{}
+
+		if err := di.Populate(&target); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if target.Int != 1 || target.Primary != 2 || target.Replica != 3 || target.Optional != 0 {
+			t.Fatalf("unexpected: %+v", target)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		target := struct {
+			Int int `di:"name=unknown"`
+		}{}
+
+		err := di.Populate(&target)
+		var notFoundErr *NotFoundError
+		if !errors.As(err, &notFoundErr) || notFoundErr.Name != "unknown" {
+			t.Fatalf("expected not found error, but got: %v", err)
+		}
+	})
+
+	t.Run("error_unknown_tag", func(t *testing.T) {
+		target := struct {
+			Int int `di:"unknown=value"`
+		}{}
+
+		if err := di.Populate(&target); err == nil || !strings.Contains(err.Error(), "unknown tag option") {
+			t.Fatalf("expected unknown tag error, but got: %v", err)
+		}
+	})
+}
+
+func TestDI_Fill(t *testing.T) {
+	buf := &bytes.Buffer{}
+	di := New().MustProvide(1)
+	if err := ProvideValue[io.Writer](di, buf); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var i int
+		if err := di.Fill(&i); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if i != 1 {
+			t.Fatalf("unexpected: %d", i)
+		}
+
+		var w io.Writer
+		if err := di.Fill(&w); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if w != buf {
+			t.Fatalf("unexpected: %v", w)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		var s string
+		if err := di.Fill(&s); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_not_pointer", func(t *testing.T) {
+		if err := di.Fill(1); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := di.Fill((*int)(nil)); err == nil {
+			t.Fatal("expected error")
+		}
+		if err := di.Fill(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -1,15 +1,57 @@
 package mdi
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// provideMap represents a map from type to it's provider
-type provideMap map[reflect.Type]*provider
+// providerKey represents unique key of provider
+type providerKey struct {
+	pType  reflect.Type
+	name   string
+	custom any
+}
+
+// String returns string representation of key
+func (k providerKey) String() string {
+	result := k.pType.String()
+	if k.name != "" {
+		result += "[" + k.name + "]"
+	}
+	if k.custom != nil {
+		result += fmt.Sprintf("{%v}", k.custom)
+	}
+	return result
+}
+
+// hash returns hash of key, keys that differ only by custom key have the same hash
+func (k providerKey) hash() uint64 {
+	return hashString(k.pType.String())*31 + hashString(k.name)
+}
+
+// describe returns description of key that is used in error messages
+func (k providerKey) describe() string {
+	result := fmt.Sprintf("type %q", k.pType.String())
+	if k.name != "" {
+		result += fmt.Sprintf(" with name %q", k.name)
+	}
+	if k.custom != nil {
+		result += fmt.Sprintf(" with key %v", k.custom)
+	}
+	return result
+}
+
+// provideMap represents a persistent map from provider key to it's provider, see [trie]
+type provideMap struct {
+	providers trie[providerKey, *provider]
+}
 
 // invoker represents function needed to get (invoke) dependency
-type invoker func(*provider, *DI) (reflect.Value, error)
+type invoker func(*provider, *DI, *resolution) (reflect.Value, error)
 
 // newProviderFromOptions creates a new provider applying all options
 func newProviderFromOptions(options []ProviderOption) *provider {
@@ -20,59 +62,172 @@ func newProviderFromOptions(options []ProviderOption) *provider {
 	return p
 }
 
+// get returns provider by key
+func (m provideMap) get(key providerKey) (*provider, bool) {
+	return m.providers.get(key.hash(), key)
+}
+
+// with returns a copy of provide map with provider set by key
+func (m provideMap) with(key providerKey, p *provider) provideMap {
+	return provideMap{providers: m.providers.with(key.hash(), key, p)}
+}
+
+// len returns number of providers
+func (m provideMap) len() int {
+	return m.providers.len()
+}
+
+// each calls function for each provider in unspecified order
+func (m provideMap) each(function func(key providerKey, p *provider)) {
+	m.providers.each(function)
+}
+
 // provider represents one dependency provider
 type provider struct {
 	eagerLoading       bool
+	lazy               bool
 	disableCache       bool
 	useRoundRobin      bool
+	fanOut             bool
+	group              bool
+	name               string
+	mapKey             any
+	namesFor           map[int]string
+	customKey          any
+	keyFunc            func() any
+	priority           int
+	timeout            time.Duration
+	retryAttempts      int
+	retryBackoff       time.Duration
+	finalizer          func(value any) error
+	onConstruct        func(value any)
+	cleanup            bool
+	cleanupPhase       int
+	staticType         bool
+	aliasOf            reflect.Type
+	redact             bool
+	tags               []string
+	isDefault          bool
+	once               bool
+	dedup              func(a, b any) bool
+	condition          func() bool
+	collectionOf       *provider
+	copy               bool
+	mapKeyOrder        reflect.Value
+	stats              providerStats
+	args               []reflect.Value
+	paramNames         []string
+	weights            []int
 	roundRobinIndex    int
+	value              reflect.Value
 	cache              reflect.Value
 	invoker            invoker
 	function           any
 	functionParamIndex int
+	decorators         []*decorator
 	mutex              sync.RWMutex
+	constructMutex     sync.Mutex
+	constructOwner     atomic.Pointer[resolution]
+}
+
+// key returns key of provider registered under type
+func (p *provider) key(pType reflect.Type) providerKey {
+	return providerKey{pType: pType, name: p.name, custom: p.customKey}
 }
 
 // setStrategyByValue sets by value strategy
 func (p *provider) setStrategyByValue(pValue reflect.Value) *provider {
+	p.value = pValue
 	p.cache = pValue
-	p.invoker = func(iP *provider, di *DI) (reflect.Value, error) {
-		return iP.cache, nil
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		iP.mutex.RLock()
+		result := iP.cache
+		iP.mutex.RUnlock()
+		if !result.IsValid() {
+			var err error
+			result, err = iP.decorate(di, res, iP.value)
+			if err != nil {
+				return result, err
+			}
+			iP.setCache(result)
+		}
+		if res.settings.stats {
+			iP.stats.hits.Add(1)
+		}
+		if iP.copy {
+			return copyValue(result), nil
+		}
+		return result, nil
 	}
 	return p
 }
 
-// setStrategyByValueRoundRobin sets by value strategy with round-robin
-func (p *provider) setStrategyByValueRoundRobin(pValue reflect.Value) *provider {
+// canCopy reports whether values of type can be copied, see [WithCopy]
+func canCopy(vType reflect.Type) bool {
+	switch vType.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return false
+	default:
+		return true
+	}
+}
+
+// copyValue returns shallow copy of value, for pointers the value they point to is copied, for slices and maps their
+// elements are copied into a new slice or map
+func copyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.New(value.Type().Elem())
+		result.Elem().Set(value.Elem())
+		return result
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		reflect.Copy(result, value)
+		return result
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			result.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return result
+	default:
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+		return result
+	}
+}
+
+// setStrategyByValueRoundRobin sets by value strategy with round-robin over values of collection
+func (p *provider) setStrategyByValueRoundRobin(pValue reflect.Value, values reflect.Value) *provider {
 	p.roundRobinIndex = -1
-	p.cache = pValue
-	p.invoker = func(iP *provider, di *DI) (reflect.Value, error) {
-		iP.mutex.Lock()
-		iP.roundRobinIndex++
-		if iP.roundRobinIndex >= iP.cache.Len() {
-			iP.roundRobinIndex = 0
+	p.cache = values
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		if res.settings.stats {
+			iP.stats.advances.Add(1)
 		}
-		iP.mutex.Unlock()
-		return iP.cache.Index(iP.roundRobinIndex), nil
+		return iP.nextRoundRobin(iP.cache, pValue.Type())
 	}
 	return p
 }
 
-// setStrategyByFunctionValue sets by function value strategy
+// setStrategyByFunctionValue sets by function value strategy, cleanup returned after the value is registered on
+// construction, see [DI.Close]
 func (p *provider) setStrategyByFunctionValue(function any, index int) *provider {
 	p.function = function
 	p.functionParamIndex = index
-	p.invoker = func(iP *provider, di *DI) (reflect.Value, error) {
-		result, iFunc := iP.getCacheOrFunction()
-		if !result.IsValid() {
-			results, err := di.invoke(iFunc)
-			if err != nil {
-				return result, err
-			}
-			result = results[iP.functionParamIndex]
-			iP.setCache(result)
-		}
-		return result, nil
+	p.cleanup = index == 0 && isCleanupConstructor(reflect.TypeOf(function))
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		return iP.construct(di, res)
 	}
 	return p
 }
@@ -81,28 +236,393 @@ func (p *provider) setStrategyByFunctionValue(function any, index int) *provider
 func (p *provider) setStrategyByFunctionValueRoundRobin(function any, index int) *provider {
 	p.function = function
 	p.functionParamIndex = index
+	p.cleanup = index == 0 && isCleanupConstructor(reflect.TypeOf(function))
 	p.roundRobinIndex = -1
-	p.invoker = func(iP *provider, di *DI) (reflect.Value, error) {
-		result, iFunc := iP.getCacheOrFunction()
-		if !result.IsValid() {
-			results, err := di.invoke(iFunc)
-			if err != nil {
-				return result, err
-			}
-			result = results[iP.functionParamIndex]
-			iP.setCache(result)
+	p.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		result, err := iP.construct(di, res)
+		if err != nil {
+			return result, err
+		}
+		if res.settings.stats {
+			iP.stats.advances.Add(1)
 		}
-		iP.mutex.Lock()
-		iP.roundRobinIndex++
-		if iP.roundRobinIndex >= result.Len() {
-			iP.roundRobinIndex = 0
+		values, err := iP.roundRobinValues(result)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-		iP.mutex.Unlock()
-		return result.Index(iP.roundRobinIndex), nil
+		return iP.nextRoundRobin(values, result.Type())
 	}
 	return p
 }
 
+// newCollectionProvider creates default provider of the whole collection constructed by round-robin function
+// provider, so collection can be resolved by its own type in addition to its elements
+func newCollectionProvider(p *provider) *provider {
+	c := &provider{
+		name:         p.name,
+		customKey:    p.customKey,
+		isDefault:    true,
+		collectionOf: p,
+	}
+	c.invoker = func(iP *provider, di *DI, res *resolution) (reflect.Value, error) {
+		return iP.collectionOf.construct(di, res)
+	}
+	return c
+}
+
+// nextRoundRobin returns the next element of collection, index wraps to the first element once it reaches the current
+// length of collection, so collections that change length between calls are handled as well, if provider has weights
+// each element is returned as many times in a row as its weight, returns an error if collection is empty or number of
+// weights doesn't match its length
+func (p *provider) nextRoundRobin(collection reflect.Value, pType reflect.Type) (reflect.Value, error) {
+	if !collection.IsValid() || collection.Len() == 0 {
+		return reflect.Value{}, newErrorEmptyRoundRobin(pType)
+	}
+
+	length := collection.Len()
+	if p.weights != nil {
+		if len(p.weights) != length {
+			return reflect.Value{}, newErrorRoundRobinWeights(pType, len(p.weights), length)
+		}
+
+		length = 0
+		for _, weight := range p.weights {
+			length += weight
+		}
+	}
+
+	p.mutex.Lock()
+	p.roundRobinIndex++
+	if p.roundRobinIndex >= length || p.roundRobinIndex < 0 {
+		p.roundRobinIndex = 0
+	}
+	index := p.roundRobinIndex
+	p.mutex.Unlock()
+
+	if p.weights != nil {
+		for i, weight := range p.weights {
+			if index < weight {
+				index = i
+				break
+			}
+			index -= weight
+		}
+	}
+
+	return collection.Index(index), nil
+}
+
+// checkWeights returns an error if provider has weights, but doesn't use round-robin or weights are invalid
+func (p *provider) checkWeights(pType reflect.Type) error {
+	if p.weights == nil {
+		return nil
+	}
+	if !p.useRoundRobin {
+		return fmt.Errorf("can't use weights for value of type %q without round-robin", pType.String())
+	}
+
+	total := 0
+	for _, weight := range p.weights {
+		if weight < 0 {
+			return fmt.Errorf("can't use negative weight %d for value of type %q", weight, pType.String())
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("can't use weights with zero sum for value of type %q", pType.String())
+	}
+	return nil
+}
+
+// roundRobinValues returns collection which elements are used for round-robin, pointers are dereferenced and maps
+// are converted to slices of their values ordered by keys (or by provider's key order, see [WithMapKeyOrder]),
+// returns invalid value for nil pointer
+func (p *provider) roundRobinValues(collection reflect.Value) (reflect.Value, error) {
+	if collection.Kind() == reflect.Ptr {
+		collection = collection.Elem()
+	}
+	if collection.Kind() != reflect.Map {
+		if p.mapKeyOrder.IsValid() && collection.IsValid() {
+			return reflect.Value{}, fmt.Errorf("can't use map key order for %q, it's not a map",
+				collection.Type().String())
+		}
+		return collection, nil
+	}
+
+	var keys []reflect.Value
+	if p.mapKeyOrder.IsValid() {
+		var err error
+		if keys, err = orderedMapKeys(collection, p.mapKeyOrder); err != nil {
+			return reflect.Value{}, err
+		}
+	} else {
+		keys = collection.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i], keys[j])
+		})
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(collection.Type().Elem()), 0, len(keys))
+	for _, key := range keys {
+		values = reflect.Append(values, collection.MapIndex(key))
+	}
+	return values, nil
+}
+
+// orderedMapKeys returns keys of map in the order, order must contain each key of map exactly once
+func orderedMapKeys(collection reflect.Value, order reflect.Value) ([]reflect.Value, error) {
+	mapType := collection.Type()
+	if order.Type().Elem() != mapType.Key() {
+		return nil, fmt.Errorf("can't use map key order of type %q for map %q", order.Type().String(),
+			mapType.String())
+	}
+	if order.Len() != collection.Len() {
+		return nil, fmt.Errorf("map key order has %d keys, but map %q has %d", order.Len(), mapType.String(),
+			collection.Len())
+	}
+
+	keys := make([]reflect.Value, 0, order.Len())
+	seen := make(map[any]bool, order.Len())
+	for i := 0; i < order.Len(); i++ {
+		key := order.Index(i)
+		if seen[key.Interface()] || !collection.MapIndex(key).IsValid() {
+			return nil, fmt.Errorf("map key order doesn't match keys of map %q, unexpected key %v", mapType.String(),
+				key.Interface())
+		}
+		seen[key.Interface()] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// lessKey reports whether map key a goes before map key b, numbers and strings are compared by value, other keys
+// by their string representation
+func lessKey(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
+
+// construct returns cached value or calls provider's function and caches the result, for cached providers function
+// is called only once even if value is requested concurrently, for fresh resolutions cache is neither used nor changed
+func (p *provider) construct(di *DI, res *resolution) (reflect.Value, error) {
+	if res.fresh != nil {
+		return p.constructFresh(di, res)
+	}
+
+	statsEnabled := res.settings.stats
+	result, _ := p.getCacheOrFunction()
+	if result.IsValid() {
+		if statsEnabled {
+			p.stats.hits.Add(1)
+		}
+		return result, nil
+	}
+
+	if !p.disableCache {
+		if err := p.lockConstruct(res); err != nil {
+			return reflect.Value{}, err
+		}
+		defer p.unlockConstruct(res)
+
+		result, _ = p.getCacheOrFunction()
+		if result.IsValid() {
+			if statsEnabled {
+				p.stats.hits.Add(1)
+			}
+			return result, nil
+		}
+	}
+
+	if statsEnabled {
+		p.stats.misses.Add(1)
+	}
+
+	function, index, cleanup := p.getFunction()
+	results, err := p.call(di, function, res)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if cleanup {
+		di.addCleanup(results[index+1].Interface().(func()), p.cleanupPhase)
+	}
+
+	result, err = p.decorate(di, res, results[index])
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	p.setCache(result)
+
+	if p.finalizer != nil {
+		if err = p.finalizer(result.Interface()); err != nil {
+			p.setCache(reflect.Value{})
+			return reflect.Value{}, fmt.Errorf("finalizer: %w", err)
+		}
+	}
+	if p.onConstruct != nil {
+		p.onConstruct(result.Interface())
+	}
+
+	return result, nil
+}
+
+// constructFresh calls provider's function without using or changing cache, value is constructed once per fresh
+// resolution unless provider is multi-instance, see [DI.InvokeFresh]
+func (p *provider) constructFresh(di *DI, res *resolution) (reflect.Value, error) {
+	if !p.disableCache {
+		if result, ok := res.fresh.get(p); ok {
+			return result, nil
+		}
+	}
+
+	if res.settings.stats {
+		p.stats.misses.Add(1)
+	}
+
+	function, index, cleanup := p.getFunction()
+	results, err := p.call(di, function, res)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if cleanup {
+		di.addCleanup(results[index+1].Interface().(func()), p.cleanupPhase)
+	}
+
+	result, err := p.decorate(di, res, results[index])
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	if p.finalizer != nil {
+		if err = p.finalizer(result.Interface()); err != nil {
+			return reflect.Value{}, fmt.Errorf("finalizer: %w", err)
+		}
+	}
+	if p.onConstruct != nil {
+		p.onConstruct(result.Interface())
+	}
+
+	if !p.disableCache {
+		res.fresh.set(p, result)
+	}
+	return result, nil
+}
+
+// call invokes provider's function, retrying it if provider has retries, see [WithRetry], retries stop when context
+// of resolution is canceled
+func (p *provider) call(di *DI, function any, res *resolution) ([]reflect.Value, error) {
+	results, err := p.callOnce(di, function, res)
+	for attempt := 1; err != nil && attempt < p.retryAttempts && !res.canceled(); attempt++ {
+		time.Sleep(p.retryBackoff)
+		results, err = p.callOnce(di, function, res)
+	}
+	return results, err
+}
+
+// callOnce invokes provider's function, if provider has timeout or resolution has context, function is called in a
+// separate goroutine and error is returned if it doesn't finish in time or context is canceled (result of such function
+// is discarded)
+func (p *provider) callOnce(di *DI, function any, res *resolution) ([]reflect.Value, error) {
+	var canceled <-chan struct{}
+	if res.ctx != nil {
+		if err := res.ctx.Err(); err != nil {
+			return nil, err
+		}
+		canceled = res.ctx.Done()
+	}
+
+	if p.timeout <= 0 && canceled == nil {
+		return di.invokeWithArgs(function, res, p.args, p.paramNames)
+	}
+
+	type callResult struct {
+		results []reflect.Value
+		err     error
+	}
+
+	done := make(chan callResult, 1)
+	go func() {
+		results, err := di.invokeWithArgs(function, res.inGoroutine(), p.args, p.paramNames)
+		done <- callResult{results: results, err: err}
+	}()
+
+	var timeout <-chan time.Time
+	if p.timeout > 0 {
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case result := <-done:
+		return result.results, result.err
+	case <-timeout:
+		return nil, newErrorTimeout(p.timeout)
+	case <-canceled:
+		return nil, res.ctx.Err()
+	}
+}
+
+// lockConstruct locks construction of provider, returns an error instead of waiting forever if construction is
+// already locked by the same resolution, this happens when provider is reached again under another key (e.g. one
+// provider registered for several interfaces) that chain doesn't show as a cycle
+func (p *provider) lockConstruct(res *resolution) error {
+	if !p.constructMutex.TryLock() {
+		if p.constructOwner.Load() == res.owner {
+			return newErrorCycleDetected(res.chain)
+		}
+		p.constructMutex.Lock()
+	}
+
+	p.constructOwner.Store(res.owner)
+	return nil
+}
+
+// unlockConstruct unlocks construction of provider, see [provider.lockConstruct]
+func (p *provider) unlockConstruct(res *resolution) {
+	p.constructOwner.Store(nil)
+	p.constructMutex.Unlock()
+}
+
+// replaceFunction replaces function of function provider keeping its cache, see [DI.ReplaceConstructor]
+func (p *provider) replaceFunction(function any, index int, cleanup bool) {
+	p.constructMutex.Lock()
+	p.mutex.Lock()
+	p.function = function
+	p.functionParamIndex = index
+	p.cleanup = cleanup
+	p.mutex.Unlock()
+	p.constructMutex.Unlock()
+}
+
+// reset clears cache of function provider, so value will be constructed again on next resolution, returns false for
+// value providers
+func (p *provider) reset() bool {
+	if !p.isFunction() {
+		return false
+	}
+
+	p.constructMutex.Lock()
+	p.mutex.Lock()
+	p.cache = reflect.Value{}
+	if p.useRoundRobin {
+		p.roundRobinIndex = -1
+	}
+	p.mutex.Unlock()
+	p.constructMutex.Unlock()
+
+	return true
+}
+
 // getCacheOrFunction returns data from cache or function to invoke
 func (p *provider) getCacheOrFunction() (reflect.Value, any) {
 	p.mutex.RLock()
@@ -113,6 +633,14 @@ func (p *provider) getCacheOrFunction() (reflect.Value, any) {
 	return p.cache, p.function
 }
 
+// getFunction returns function to invoke alongside with index of provided value in its results and whether it also
+// returns cleanup, see [DI.ReplaceConstructor]
+func (p *provider) getFunction() (any, int, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.function, p.functionParamIndex, p.cleanup
+}
+
 // setCache sets data into cache
 func (p *provider) setCache(data reflect.Value) {
 	if p.disableCache {
@@ -120,11 +648,121 @@ func (p *provider) setCache(data reflect.Value) {
 	}
 	p.mutex.Lock()
 	p.cache = data
-	p.function = nil
 	p.mutex.Unlock()
 }
 
+// dependencies returns keys of function parameters that provider depends on, see [functionDependencies]
+func (p *provider) dependencies() []providerKey {
+	if p.aliasOf != nil {
+		return []providerKey{{pType: p.aliasOf}}
+	}
+	if p.collectionOf != nil {
+		return p.collectionOf.dependencies()
+	}
+	function, _, _ := p.getFunction()
+	if function == nil {
+		return nil
+	}
+	return functionDependencies(reflect.TypeOf(function), p.paramNames)
+}
+
+// functionDependencies returns keys of parameters of function type (with names of parameters, see [WithParamNames]),
+// fields of [In] parameters are used instead of parameter itself, variadic parameter and optional fields are omitted
+func functionDependencies(fType reflect.Type, names []string) []providerKey {
+	numIn := fType.NumIn()
+	if fType.IsVariadic() {
+		numIn--
+	}
+
+	deps := make([]providerKey, 0, numIn)
+	for i := 0; i < numIn; i++ {
+		param := fType.In(i)
+		if !isIn(param) {
+			deps = append(deps, providerKey{pType: param, name: paramName(names, i)})
+			continue
+		}
+
+		fields, _ := inFields(param)
+		for _, field := range fields {
+			if !field.optional {
+				deps = append(deps, field.key)
+			}
+		}
+	}
+	return deps
+}
+
+// isActive reports whether provider can be used for resolution, see [WithConditional]
+func (p *provider) isActive() bool {
+	return p.condition == nil || p.condition()
+}
+
+// isFunction reports whether provider is a function provider
+func (p *provider) isFunction() bool {
+	function, _, _ := p.getFunction()
+	return function != nil
+}
+
+// isCached reports whether provider is a function provider with already constructed value in cache
+func (p *provider) isCached() bool {
+	result, function := p.getCacheOrFunction()
+	return function != nil && result.IsValid()
+}
+
+// clone returns a copy of provider, cache of function providers is not copied
+func (p *provider) clone() *provider {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	c := &provider{
+		eagerLoading:       p.eagerLoading,
+		lazy:               p.lazy,
+		disableCache:       p.disableCache,
+		useRoundRobin:      p.useRoundRobin,
+		fanOut:             p.fanOut,
+		group:              p.group,
+		name:               p.name,
+		mapKey:             p.mapKey,
+		namesFor:           p.namesFor,
+		customKey:          p.customKey,
+		priority:           p.priority,
+		timeout:            p.timeout,
+		retryAttempts:      p.retryAttempts,
+		retryBackoff:       p.retryBackoff,
+		finalizer:          p.finalizer,
+		onConstruct:        p.onConstruct,
+		cleanup:            p.cleanup,
+		cleanupPhase:       p.cleanupPhase,
+		aliasOf:            p.aliasOf,
+		redact:             p.redact,
+		tags:               p.tags,
+		isDefault:          p.isDefault,
+		once:               p.once,
+		dedup:              p.dedup,
+		condition:          p.condition,
+		collectionOf:       p.collectionOf,
+		copy:               p.copy,
+		mapKeyOrder:        p.mapKeyOrder,
+		args:               p.args,
+		paramNames:         p.paramNames,
+		weights:            p.weights,
+		roundRobinIndex:    p.roundRobinIndex,
+		value:              p.value,
+		invoker:            p.invoker,
+		function:           p.function,
+		functionParamIndex: p.functionParamIndex,
+		decorators:         append([]*decorator(nil), p.decorators...),
+	}
+	if p.function == nil {
+		c.cache = p.cache
+	} else if p.useRoundRobin {
+		c.roundRobinIndex = -1
+	}
+
+	return c
+}
+
 // provide data using invoker
-func (p *provider) provide(di *DI) (reflect.Value, error) {
-	return p.invoker(p, di)
+func (p *provider) provide(di *DI, res *resolution) (reflect.Value, error) {
+	return p.invoker(p, di, res)
 }
@@ -1,5 +1,10 @@
 package mdi
 
+import (
+	"reflect"
+	"time"
+)
+
 // ProviderOption represents provider options
 type ProviderOption func(p *provider)
 
@@ -7,6 +12,24 @@ type ProviderOption func(p *provider)
 func WithEagerLoading() ProviderOption {
 	return func(p *provider) {
 		p.eagerLoading = true
+		p.lazy = false
+	}
+}
+
+// WithEagerLoadingIf provider's option to eager load dependency if condition is true (see [WithEagerLoading]) and to
+// load it only when it's used otherwise (see [WithLazy]), e.g. to skip construction of heavy dependencies in tests
+func WithEagerLoadingIf(condition bool) ProviderOption {
+	if condition {
+		return WithEagerLoading()
+	}
+	return WithLazy()
+}
+
+// WithLazy provider's option to load dependency only when it's used, even if container is eager by default
+func WithLazy() ProviderOption {
+	return func(p *provider) {
+		p.eagerLoading = false
+		p.lazy = true
 	}
 }
 
@@ -17,9 +40,241 @@ func WithMultiInstance() ProviderOption {
 	}
 }
 
-// WithRoundRobin provider's option for round-robin dependency
+// WithTransient provider's option to construct a new instance on each resolution, same as [WithMultiInstance]
+func WithTransient() ProviderOption {
+	return WithMultiInstance()
+}
+
+// WithSingleton provider's option to construct only one instance and cache it (default), useful to override options
+// that disable caching
+func WithSingleton() ProviderOption {
+	return func(p *provider) {
+		p.disableCache = false
+	}
+}
+
+// WithCache provider's option to enable (default) or disable caching, WithCache(false) is the same as
+// [WithMultiInstance], to bypass cache for a single call without changing provider use [DI.InvokeFresh]
+func WithCache(enabled bool) ProviderOption {
+	return func(p *provider) {
+		p.disableCache = !enabled
+	}
+}
+
+// WithRoundRobin provider's option for round-robin dependency, each resolution of element type returns the next
+// element, the whole collection is still resolvable by its own type unless there is another provider of that type
 func WithRoundRobin() ProviderOption {
 	return func(p *provider) {
 		p.useRoundRobin = true
 	}
 }
+
+// WithFanOut provider's option for round-robin over channels, provided value must be a slice, an array or a map of
+// channels, each resolution of channel type returns the next channel, so workers are distributed across them
+func WithFanOut() ProviderOption {
+	return func(p *provider) {
+		p.useRoundRobin = true
+		p.fanOut = true
+	}
+}
+
+// WithMapKeyOrder provider's option for round-robin over map to rotate values in order of keys instead of sorted
+// order, keys must contain each key of the map exactly once
+func WithMapKeyOrder[K comparable](keys []K) ProviderOption {
+	return func(p *provider) {
+		p.mapKeyOrder = reflect.ValueOf(keys)
+	}
+}
+
+// WithWeights provider's option for weighted round-robin dependency, must be used with [WithRoundRobin], each
+// element is selected proportionally to its weight, number of weights must match number of elements
+func WithWeights(weights []int) ProviderOption {
+	return func(p *provider) {
+		p.weights = append([]int{}, weights...)
+	}
+}
+
+// WithStaticType provider's option to register value under its declared type instead of dynamic type, provided value
+// must be a non-nil pointer to the value (e.g. for var r io.Reader = os.Stdin, Provide(&r, WithStaticType())
+// registers r under io.Reader instead of *os.File)
+func WithStaticType() ProviderOption {
+	return func(p *provider) {
+		p.staticType = true
+	}
+}
+
+// WithPriority provider's option to set priority (default is 0), provider with higher priority replaces existing
+// provider of the same type, provider with lower priority is ignored and providers with equal priorities conflict
+func WithPriority(priority int) ProviderOption {
+	return func(p *provider) {
+		p.priority = priority
+	}
+}
+
+// WithTimeout provider's option to fail construction of dependency if it takes longer than timeout
+func WithTimeout(timeout time.Duration) ProviderOption {
+	return func(p *provider) {
+		p.timeout = timeout
+	}
+}
+
+// WithRetry provider's option to call function of provider again if construction fails, function is called at most
+// attempts times waiting backoff between calls, the last error is returned if all attempts fail, concurrent
+// resolutions of cached provider wait for retries instead of starting their own
+func WithRetry(attempts int, backoff time.Duration) ProviderOption {
+	return func(p *provider) {
+		p.retryAttempts = attempts
+		p.retryBackoff = backoff
+	}
+}
+
+// WithName provider's option to register provider under name, named providers are distinct from unnamed providers of
+// the same type and can be resolved only by name
+func WithName(name string) ProviderOption {
+	return func(p *provider) {
+		p.name = name
+	}
+}
+
+// WithKeyFunc provider's option to register provider under custom key returned by keyFunc (called once on provide)
+// in addition to its type, key must be comparable, providers with custom keys are distinct from other providers of
+// the same type and can be resolved only by key using [ResolveByKey]
+func WithKeyFunc(keyFunc func() any) ProviderOption {
+	return func(p *provider) {
+		p.keyFunc = keyFunc
+	}
+}
+
+// WithNameFor provider's option to register only return value of function provider with index (counting all return
+// values) under name, it overrides [WithName] for that return value, can be used multiple times for different indexes
+func WithNameFor(returnIndex int, name string) ProviderOption {
+	return func(p *provider) {
+		if p.namesFor == nil {
+			p.namesFor = map[int]string{}
+		}
+		p.namesFor[returnIndex] = name
+	}
+}
+
+// WithGroup provider's option to add provider as a member of group of its type instead of registering it as a
+// unique provider, all members of the group are resolved as a slice of that type (or as a variadic parameter)
+func WithGroup() ProviderOption {
+	return func(p *provider) {
+		p.group = true
+	}
+}
+
+// WithDedup provider's option for group members to skip value of member if an equal value (using
+// [reflect.DeepEqual]) is already present in the group, e.g. when the same plugin is registered twice
+func WithDedup() ProviderOption {
+	return WithDedupFunc(reflect.DeepEqual)
+}
+
+// WithDedupFunc is like [WithDedup], but uses custom function to compare values of group members
+func WithDedupFunc(equal func(a, b any) bool) ProviderOption {
+	return func(p *provider) {
+		p.dedup = equal
+	}
+}
+
+// WithConditional provider's option to make provider active only while condition returns true, condition is checked
+// on each resolution, inactive provider is treated as absent, so resolution falls through to parents or fails as not
+// found
+func WithConditional(condition func() bool) ProviderOption {
+	return func(p *provider) {
+		p.condition = condition
+	}
+}
+
+// WithCleanupPhase provider's option to set phase of cleanup function returned by constructor, on [DI.Close] cleanups
+// of lower phases are called first regardless of construction order, default phase is zero
+func WithCleanupPhase(phase int) ProviderOption {
+	return func(p *provider) {
+		p.cleanupPhase = phase
+	}
+}
+
+// WithOnConstruct provider's option to call hook each time function provider constructs a value (after finalizer,
+// see [WithFinalizer]), so it's called once for cached providers and for each instance of multi-instance providers,
+// for round-robin it's called once with the whole collection
+func WithOnConstruct(hook func(value any)) ProviderOption {
+	return func(p *provider) {
+		p.onConstruct = hook
+	}
+}
+
+// WithCopy provider's option for value providers to return a shallow copy of value on each resolution, so mutations
+// of resolved value don't affect provided one, for pointers the value they point to is copied, for slices and maps
+// their elements, channels, functions and interfaces can't be copied
+func WithCopy() ProviderOption {
+	return func(p *provider) {
+		p.copy = true
+	}
+}
+
+// WithFinalizer provider's option to call finalizer right after function provider constructs a value (for round-robin
+// it's called once with the whole collection), error returned by finalizer fails resolution and value is not cached
+func WithFinalizer(finalizer func(value any) error) ProviderOption {
+	return func(p *provider) {
+		p.finalizer = finalizer
+	}
+}
+
+// WithKey provider's option to add provider to the keyed collection of its type under non-nil key instead of
+// registering it as a unique provider, collection can be resolved as a map using [ResolveMap]
+func WithKey(key any) ProviderOption {
+	return func(p *provider) {
+		p.mapKey = key
+	}
+}
+
+// WithConstructorArgs provider's option to pass fixed arguments to function provider, container has precedence, so
+// arguments are used only for parameters that can't be resolved from the container, each such parameter takes the
+// first not yet used argument assignable to its type (e.g. for func(db *DB, port int, host string) registered with
+// WithConstructorArgs("localhost", 8080) db is resolved from the container and port and host from arguments)
+func WithConstructorArgs(args ...any) ProviderOption {
+	return func(p *provider) {
+		p.args = make([]reflect.Value, 0, len(args))
+		for _, arg := range args {
+			p.args = append(p.args, reflect.ValueOf(arg))
+		}
+	}
+}
+
+// WithTags provider's option to attach arbitrary tags to provider, tags don't affect resolution, providers can be
+// listed by tag using [DI.ProvidersByTag]
+func WithTags(tags ...string) ProviderOption {
+	return func(p *provider) {
+		p.tags = append(p.tags, tags...)
+	}
+}
+
+// WithParamNames provider's option for function providers to resolve parameters by names, name of each parameter is
+// at its index (e.g. constructor func(primary, replica *DB) with WithParamNames("primary", "replica")), empty names
+// and parameters without names are resolved as usual
+func WithParamNames(names ...string) ProviderOption {
+	return func(p *provider) {
+		p.paramNames = names
+	}
+}
+
+// WithRedact provider's option to hide value of provider in [DI.Snapshot], useful for secrets
+func WithRedact() ProviderOption {
+	return func(p *provider) {
+		p.redact = true
+	}
+}
+
+// asDefault provider's option to register provider as default, see [DI.ProvideDefault]
+func asDefault() ProviderOption {
+	return func(p *provider) {
+		p.isDefault = true
+	}
+}
+
+// asOnce provider's option to never replace existing provider, see [DI.ProvideOnce]
+func asOnce() ProviderOption {
+	return func(p *provider) {
+		p.once = true
+	}
+}
@@ -0,0 +1,186 @@
+package mdi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// resolution represents state of a single dependency resolution
+type resolution struct {
+	scope    *DI
+	chain    []providerKey
+	settings settings
+	fresh    *freshCache
+	ctx      context.Context
+
+	// owner is resolution started by [newResolution] that this resolution continues, it's used to detect construction
+	// locks that are already held by the same resolution, see [provider.lockConstruct]
+	owner *resolution
+
+	// inCall reports whether resolution resolves parameters of function call, see [resolution.forCall]
+	inCall bool
+	// bindings are resolvers and container views injected into function call, they're unbound once function returns
+	bindings []*boundResolver
+}
+
+// settings represents settings of container inherited from its parents, they're found once when resolution starts, so
+// parents aren't walked on each construction
+type settings struct {
+	maxDepth      int
+	observer      Observer
+	stats         bool
+	recoverPanics bool
+}
+
+// freshCache represents values constructed during one fresh resolution, so each provider is constructed only once
+// within it, see [DI.InvokeFresh]
+type freshCache struct {
+	mutex  sync.Mutex
+	values map[*provider]reflect.Value
+}
+
+// get returns value constructed by provider during fresh resolution
+func (c *freshCache) get(p *provider) (reflect.Value, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	value, ok := c.values[p]
+	return value, ok
+}
+
+// set stores value constructed by provider during fresh resolution
+func (c *freshCache) set(p *provider, value reflect.Value) {
+	c.mutex.Lock()
+	c.values[p] = value
+	c.mutex.Unlock()
+}
+
+// newResolution creates a new resolution started from scope container, if scope is a view of container bound to
+// resolution of function that is still being called, that resolution is continued instead, see [DI.view]
+func newResolution(scope *DI, chain ...providerKey) *resolution {
+	if binding := scope.binding; binding != nil {
+		if !binding.done.Load() {
+			return binding.res.continued(chain)
+		}
+		scope = scope.target
+	}
+
+	res := &resolution{
+		scope:    scope,
+		chain:    chain,
+		settings: scope.inheritedSettings(),
+	}
+	res.owner = res
+	return res
+}
+
+// continued returns a copy of resolution with keys added to the chain, that continues it outside of function call
+func (r *resolution) continued(chain []providerKey) *resolution {
+	return &resolution{
+		scope:    r.scope,
+		chain:    append(r.chain[:len(r.chain):len(r.chain)], chain...),
+		settings: r.settings,
+		fresh:    r.fresh,
+		ctx:      r.ctx,
+		owner:    r.owner,
+	}
+}
+
+// inGoroutine returns a copy of resolution that continues it in a separate goroutine
+func (r *resolution) inGoroutine() *resolution {
+	return r.continued(nil)
+}
+
+// forCall returns a copy of resolution that resolves parameters of one function call, so resolvers and container views
+// injected into it (including fields of parameter objects) are unbound once function returns, see [resolution.unbind]
+func (r *resolution) forCall() *resolution {
+	return &resolution{
+		scope:    r.scope,
+		chain:    r.chain,
+		settings: r.settings,
+		fresh:    r.fresh,
+		ctx:      r.ctx,
+		owner:    r.owner,
+		inCall:   true,
+	}
+}
+
+// unbind marks resolvers and container views injected into function call as done, since function already returned
+func (r *resolution) unbind() {
+	for _, binding := range r.bindings {
+		binding.done.Store(true)
+	}
+}
+
+// push returns a new resolution with key added to the chain or error if key is already being resolved (cycle) or
+// chain becomes longer than max depth
+func (r *resolution) push(key providerKey) (*resolution, error) {
+	for _, chainKey := range r.chain {
+		if chainKey == key {
+			return nil, newErrorCycleDetected(append(r.chain, key))
+		}
+	}
+	if r.settings.maxDepth > 0 && len(r.chain) >= r.settings.maxDepth {
+		return nil, newErrorMaxDepthExceeded(r.settings.maxDepth, append(r.chain, key))
+	}
+	return &resolution{
+		scope:    r.scope,
+		chain:    append(r.chain[:len(r.chain):len(r.chain)], key),
+		settings: r.settings,
+		fresh:    r.fresh,
+		ctx:      r.ctx,
+		owner:    r.owner,
+	}, nil
+}
+
+// canceled reports whether context of resolution is canceled, see [DI.InvokeContext]
+func (r *resolution) canceled() bool {
+	return r.ctx != nil && r.ctx.Err() != nil
+}
+
+// wrapError adds chain of resolution to the error, so it's clear how the failed dependency was reached, errors of
+// resolutions without chain (e.g. top-level invoke) are returned as is
+func (r *resolution) wrapError(err error) error {
+	if len(r.chain) == 0 {
+		return err
+	}
+	return fmt.Errorf("resolving %s: %w", formatChain(r.chain), err)
+}
+
+// SetMaxDepth limits how many dependencies can be resolved one inside another for resolutions started from container
+// or its children, zero or negative depth means unlimited (default)
+func (d *DI) SetMaxDepth(depth int) {
+	_ = d.update(func(s *state) error {
+		s.maxDepth = depth
+		return nil
+	})
+}
+
+// inheritedSettings returns settings of container, each setting is taken from container or the closest parent that
+// has it, see [DI.SetMaxDepth], [DI.SetObserver], [DI.EnableStats] and [WithRecover]
+func (d *DI) inheritedSettings() settings {
+	var result settings
+	for di := d; di != nil; di = di.parent {
+		s := di.load()
+		if result.maxDepth <= 0 {
+			result.maxDepth = s.maxDepth
+		}
+		if result.observer == nil {
+			result.observer = s.observer
+		}
+		result.stats = result.stats || s.stats
+		result.recoverPanics = result.recoverPanics || s.recoverPanics
+	}
+	return result
+}
+
+// formatChain returns string representation of keys chain
+func formatChain(chain []providerKey) string {
+	names := make([]string, 0, len(chain))
+	for _, chainKey := range chain {
+		names = append(names, chainKey.String())
+	}
+	return strings.Join(names, " -> ")
+}
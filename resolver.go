@@ -0,0 +1,81 @@
+package mdi
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Resolver represents minimal container interface that can be requested by constructors instead of [DI] to resolve
+// dependencies manually (e.g. optional ones), it resolves from container that resolution was started from, while
+// function it's injected into is called, it continues resolution of the function, so cycles are detected, context
+// (see [DI.InvokeContext]) and fresh values (see [DI.InvokeFresh]) are kept, after that it's the same as [DI.Resolve]
+type Resolver interface {
+	// Resolve returns dependency of type from container
+	Resolve(pType reflect.Type) (reflect.Value, error)
+}
+
+// Provider represents minimal container interface that can be requested by constructors instead of [DI] to add
+// providers, it's resolved as container that resolution was started from
+type Provider interface {
+	// Provide adds provider of value or function to container
+	Provide(provide any, options ...ProviderOption) error
+}
+
+var (
+	// resolverKey represents key of [Resolver]
+	resolverKey = providerKey{pType: typeOf[Resolver]()}
+	// providerIfaceKey represents key of [Provider]
+	providerIfaceKey = providerKey{pType: typeOf[Provider]()}
+)
+
+// Resolve returns dependency of type from container, constructing it if necessary
+func (d *DI) Resolve(pType reflect.Type) (reflect.Value, error) {
+	return d.invokeParam(pType, 0, newResolution(d))
+}
+
+// isContainerKey reports whether key is a key of interface implemented by container, see [Resolver] and [Provider]
+func isContainerKey(key providerKey) bool {
+	return key == resolverKey || key == providerIfaceKey
+}
+
+// containerValue returns container as value of type of key, [Resolver] is bound to resolution of function call until
+// function returns, [DI] and [Provider] are bound the same way if function is called during construction, see [DI.view]
+func containerValue(res *resolution, key providerKey) reflect.Value {
+	switch {
+	case key == resolverKey:
+		resolver := &boundResolver{res: res}
+		if res.inCall {
+			res.bindings = append(res.bindings, resolver)
+		} else {
+			resolver.done.Store(true)
+		}
+		return reflect.ValueOf(resolver).Convert(key.pType)
+	case res.inCall && len(res.chain) != 0:
+		return reflect.ValueOf(res.scope.view(res)).Convert(key.pType)
+	default:
+		return reflect.ValueOf(res.scope).Convert(key.pType)
+	}
+}
+
+// view returns view of container bound to resolution of function call, view acts as container itself, but resolutions
+// started from it continue bound resolution until function returns, so constructor that resolves its own provider
+// through injected container gets [ErrCycleDetected] instead of waiting for itself forever
+func (d *DI) view(res *resolution) *DI {
+	binding := &boundResolver{res: res}
+	res.bindings = append(res.bindings, binding)
+	return &DI{parent: d.parent, target: d, binding: binding}
+}
+
+// boundResolver represents [Resolver] that continues resolution until function it's injected into returns
+type boundResolver struct {
+	res  *resolution
+	done atomic.Bool
+}
+
+// Resolve returns dependency of type from container continuing bound resolution if function wasn't returned yet
+func (r *boundResolver) Resolve(pType reflect.Type) (reflect.Value, error) {
+	if r.done.Load() {
+		return r.res.scope.Resolve(pType)
+	}
+	return r.res.scope.invokeParam(pType, 0, r.res)
+}
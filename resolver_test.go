@@ -0,0 +1,192 @@
+package mdi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDI_Resolver(t *testing.T) {
+	constructor := func(r Resolver) string {
+		value, err := r.Resolve(typeOf[int]())
+		if errors.Is(err, ErrProviderNotFound) {
+			return "default"
+		}
+		return fmt.Sprint("value ", value.Interface())
+	}
+
+	t.Run("optional_missing", func(t *testing.T) {
+		di := New().MustProvide(constructor)
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[string](di); value != "default" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("optional_present", func(t *testing.T) {
+		di := New().MustProvide(constructor).MustProvide(1)
+		if value := MustResolve[string](di); value != "value 1" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		di := New().MustProvide(constructor, WithMultiInstance())
+		if value := MustResolve[string](di.MustWithValue(1)); value != "value 1" {
+			t.Fatalf("unexpected: %q", value)
+		}
+	})
+
+	t.Run("di", func(t *testing.T) {
+		di := New()
+		scope := di.Scope()
+		if err := scope.Invoke(func(d *DI) {
+			if d != scope {
+				t.Fatal("unexpected container")
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+	t.Run("provider", func(t *testing.T) {
+		di := New().MustProvide(func(p Provider, r Resolver) (string, error) {
+			if err := p.Provide(1); err != nil {
+				return "", err
+			}
+			value, err := r.Resolve(typeOf[int]())
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprint("value ", value.Interface()), nil
+		})
+		if err := di.Validate(); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[string](di); value != "value 1" {
+			t.Fatalf("unexpected: %q", value)
+		}
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	})
+
+	t.Run("interfaces", func(t *testing.T) {
+		di := New()
+		if err := di.Invoke(func(d *DI, r Resolver, p Provider) {
+			if r == nil || p.(*DI) != d {
+				t.Fatal("unexpected container")
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+	t.Run("cycle", func(t *testing.T) {
+		di := New().
+			MustProvide(func(r Resolver) (int, error) {
+				_, err := r.Resolve(typeOf[string]())
+				return 1, err
+			}).
+			MustProvide(func(int) string { return "" })
+
+		_, err := Resolve[int](di)
+		if !errors.Is(err, ErrCycleDetected) {
+			t.Fatalf("expected error: %q, but got: %v", ErrCycleDetected, err)
+		}
+		if !strings.Contains(err.Error(), "int -> string -> int") {
+			t.Fatalf("expected chain in error, but got: %q", err)
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		di := New().MustProvide(func() int { return 1 })
+		err := di.InvokeContext(ctx, func(r Resolver) error {
+			cancel()
+			_, err := r.Resolve(typeOf[int]())
+			return err
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected error: %q, but got: %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("fresh", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() int { calls++; return calls })
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+
+		if err := di.InvokeFresh(func(r Resolver) {
+			value, err := r.Resolve(typeOf[int]())
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if value.Interface() != 2 {
+				t.Fatalf("unexpected: %v", value)
+			}
+		}); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+	})
+
+	t.Run("after_return", func(t *testing.T) {
+		di := New().MustProvide(1)
+
+		var resolver Resolver
+		di.MustInvoke(func(r Resolver) { resolver = r })
+
+		value, err := resolver.Resolve(typeOf[int]())
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value.Interface() != 1 {
+			t.Fatalf("unexpected: %v", value)
+		}
+	})
+	t.Run("after_return_in", func(t *testing.T) {
+		type params struct {
+			In
+			R Resolver
+		}
+
+		var resolver Resolver
+		di := New().MustProvide(func(p params) int { resolver = p.R; return 1 })
+		if value := MustResolve[int](di); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+
+		value, err := resolver.Resolve(typeOf[int]())
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value.Interface() != 1 {
+			t.Fatalf("unexpected: %v", value)
+		}
+	})
+
+	t.Run("after_return_decorator", func(t *testing.T) {
+		var resolver Resolver
+		di := New().MustProvide(func() int { return 1 })
+		if err := di.Decorate(func(i int, r Resolver) int { resolver = r; return i + 1 }); err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value := MustResolve[int](di); value != 2 {
+			t.Fatalf("unexpected: %d", value)
+		}
+
+		value, err := resolver.Resolve(typeOf[int]())
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value.Interface() != 2 {
+			t.Fatalf("unexpected: %v", value)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package mdi
+
+import "reflect"
+
+// state represents snapshot of container's providers and settings, stored state is never modified, instead, each
+// change stores a modified copy of it, so state can be read without locks
+type state struct {
+	provide        provideMap
+	groups         groupMap
+	keyed          groupMap
+	observer       Observer
+	eager          bool
+	assignable     bool
+	maxDepth       int
+	allowOverride  bool
+	onProvide      func(pType reflect.Type) error
+	recoverPanics  bool
+	stats          bool
+	linkCache      bool
+	defaultOptions []ProviderOption
+}
+
+// emptyState represents state of container without providers
+var emptyState = &state{}
+
+// load returns current state of container
+func (d *DI) load() *state {
+	if d.target != nil {
+		return d.target.load()
+	}
+	if s := d.state.Load(); s != nil {
+		return s
+	}
+	return emptyState
+}
+
+// update replaces state of container with its modified copy, maps of state are persistent, so they are changed by
+// replacing them with their changed copies, if modify returns an error state is left unchanged
+func (d *DI) update(modify func(s *state) error) error {
+	if d.target != nil {
+		return d.target.update(modify)
+	}
+
+	d.provideMutex.Lock()
+	defer d.provideMutex.Unlock()
+
+	next := *d.load()
+	if err := modify(&next); err != nil {
+		return err
+	}
+
+	d.state.Store(&next)
+	return nil
+}
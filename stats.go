@@ -0,0 +1,59 @@
+package mdi
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Stats represents resolution statistics of container
+type Stats struct {
+	// ByType are statistics of providers by their types (statistics of named providers of the same type are summed)
+	ByType map[reflect.Type]TypeStats
+}
+
+// TypeStats represents resolution statistics of providers of one type
+type TypeStats struct {
+	// Hits is number of resolutions that returned already constructed value
+	Hits int64
+	// Misses is number of constructions
+	Misses int64
+	// RoundRobinAdvances is number of resolutions of round-robin providers
+	RoundRobinAdvances int64
+}
+
+// providerStats represents resolution statistics of provider
+type providerStats struct {
+	hits     atomic.Int64
+	misses   atomic.Int64
+	advances atomic.Int64
+}
+
+// EnableStats makes resolutions started from container and its children collect statistics of providers they resolve
+// (including providers of parents), statistics are disabled by default to avoid overhead
+func (d *DI) EnableStats() {
+	_ = d.update(func(s *state) error {
+		s.stats = true
+		return nil
+	})
+}
+
+// Stats returns resolution statistics of providers of container (without parents) collected since statistics were
+// enabled, providers without resolutions are not included
+func (d *DI) Stats() Stats {
+	stats := Stats{
+		ByType: map[reflect.Type]TypeStats{},
+	}
+	d.providers().each(func(key providerKey, p *provider) {
+		hits, misses, advances := p.stats.hits.Load(), p.stats.misses.Load(), p.stats.advances.Load()
+		if hits == 0 && misses == 0 && advances == 0 {
+			return
+		}
+
+		typeStats := stats.ByType[key.pType]
+		typeStats.Hits += hits
+		typeStats.Misses += misses
+		typeStats.RoundRobinAdvances += advances
+		stats.ByType[key.pType] = typeStats
+	})
+	return stats
+}
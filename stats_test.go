@@ -0,0 +1,51 @@
+package mdi
+
+import (
+	"testing"
+)
+
+func TestDI_Stats(t *testing.T) {
+	di := New().
+		MustProvide(1).
+		MustProvide(func(i int) string { return "" }).
+		MustProvide([]float64{1, 2}, WithRoundRobin())
+
+	di.MustInvoke(func(s string) {})
+	if len(di.Stats().ByType) != 0 {
+		t.Fatalf("unexpected stats: %+v", di.Stats())
+	}
+
+	di.EnableStats()
+	if !di.Reset(typeOf[string]()) {
+		t.Fatalf("expected reset")
+	}
+	di.MustInvoke(func(s string) {})
+	di.MustInvoke(func(s string, f1, f2, f3 float64) {})
+
+	stats := di.Stats()
+	if s := stats.ByType[typeOf[string]()]; s.Hits != 1 || s.Misses != 1 {
+		t.Fatalf("unexpected: %+v", s)
+	}
+	if s := stats.ByType[typeOf[int]()]; s.Hits != 1 || s.Misses != 0 {
+		t.Fatalf("unexpected: %+v", s)
+	}
+	if s := stats.ByType[typeOf[float64]()]; s.RoundRobinAdvances != 3 {
+		t.Fatalf("unexpected: %+v", s)
+	}
+}
+
+func TestDI_StatsScope(t *testing.T) {
+	parent := New().MustProvide(1)
+	child := parent.Scope()
+	child.EnableStats()
+
+	parent.MustInvoke(func(i int) {})
+	if len(parent.Stats().ByType) != 0 {
+		t.Fatalf("unexpected stats: %+v", parent.Stats())
+	}
+
+	child.MustInvoke(func(i int) {})
+	if s := parent.Stats().ByType[typeOf[int]()]; s.Hits != 1 {
+		t.Fatalf("unexpected: %+v", s)
+	}
+}
@@ -0,0 +1,42 @@
+package mdi
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// TestDI represents container for tests, it allows overriding providers and records all resolutions
+type TestDI struct {
+	*DI
+
+	mutex       sync.Mutex
+	resolutions []reflect.Type
+}
+
+// NewTestDI creates a new container for tests, providing the same type again replaces existing provider instead of
+// returning an error (see [WithAllowOverride]), so mocks can easily replace real providers
+func NewTestDI() *TestDI {
+	d := &TestDI{
+		DI: New(WithAllowOverride()),
+	}
+	d.SetObserver(d)
+	return d
+}
+
+// Resolutions returns types of all resolved dependencies in order of resolution start
+func (d *TestDI) Resolutions() []reflect.Type {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]reflect.Type(nil), d.resolutions...)
+}
+
+// OnResolveStart records resolution of dependency, implements [Observer]
+func (d *TestDI) OnResolveStart(pType reflect.Type) {
+	d.mutex.Lock()
+	d.resolutions = append(d.resolutions, pType)
+	d.mutex.Unlock()
+}
+
+// OnResolveEnd implements [Observer]
+func (d *TestDI) OnResolveEnd(_ reflect.Type, _ error, _ time.Duration) {}
@@ -0,0 +1,33 @@
+package mdi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTestDI(t *testing.T) {
+	di := NewTestDI()
+	di.MustProvide(1)
+	di.MustProvide(func(i int) string { return "real" })
+	di.MustProvide(2)
+	di.MustProvide(func() string { return "mock" })
+
+	di.MustInvoke(func(i int, s string) {
+		if i != 2 {
+			t.Fatalf("unexpected: %d", i)
+		}
+		if s != "mock" {
+			t.Fatalf("unexpected: %q", s)
+		}
+	})
+
+	expected := []reflect.Type{typeOf[int](), typeOf[string]()}
+	if resolutions := di.Resolutions(); !reflect.DeepEqual(resolutions, expected) {
+		t.Fatalf("expected resolutions: %v, but got: %v", expected, resolutions)
+	}
+
+	di.MustProvide(3, WithPriority(-1))
+	if value := MustResolve[int](di.DI); value != 2 {
+		t.Fatalf("unexpected: %d", value)
+	}
+}
@@ -0,0 +1,164 @@
+package mdi
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	// trieBits represents number of hash bits used on each level of trie
+	trieBits = 5
+	// trieMask represents mask of hash bits used on each level of trie
+	trieMask = 1<<trieBits - 1
+)
+
+// hashSeed represents seed used to hash keys of tries
+var hashSeed = maphash.MakeSeed()
+
+// trie represents persistent hash map, it's never modified, instead, each change returns a modified copy of it that
+// shares all unchanged nodes with the original, so change copies only nodes on the path to the key, zero value is an
+// empty trie
+type trie[K comparable, V any] struct {
+	root *trieNode[K, V]
+	size int
+}
+
+// trieNode represents one level of trie, entries are stored only for set bits of bitmap
+type trieNode[K comparable, V any] struct {
+	bitmap  uint32
+	entries []trieEntry[K, V]
+}
+
+// trieEntry represents either the next level of trie or leaf with items
+type trieEntry[K comparable, V any] struct {
+	node *trieNode[K, V]
+	leaf *trieLeaf[K, V]
+}
+
+// trieLeaf represents items with the same hash
+type trieLeaf[K comparable, V any] struct {
+	hash  uint64
+	items []trieItem[K, V]
+}
+
+// trieItem represents key with its value
+type trieItem[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// len returns number of keys in trie
+func (t trie[K, V]) len() int {
+	return t.size
+}
+
+// get returns value of key with hash
+func (t trie[K, V]) get(hash uint64, key K) (V, bool) {
+	for node, shift := t.root, 0; node != nil; shift += trieBits {
+		bit := uint32(1) << ((hash >> shift) & trieMask)
+		if node.bitmap&bit == 0 {
+			break
+		}
+
+		entry := &node.entries[bits.OnesCount32(node.bitmap&(bit-1))]
+		if entry.node != nil {
+			node = entry.node
+			continue
+		}
+		if entry.leaf.hash == hash {
+			for _, item := range entry.leaf.items {
+				if item.key == key {
+					return item.value, true
+				}
+			}
+		}
+		break
+	}
+
+	var value V
+	return value, false
+}
+
+// with returns a copy of trie with value set for key with hash
+func (t trie[K, V]) with(hash uint64, key K, value V) trie[K, V] {
+	root := t.root
+	if root == nil {
+		root = &trieNode[K, V]{}
+	}
+
+	root, added := root.with(0, hash, key, value)
+	if added {
+		return trie[K, V]{root: root, size: t.size + 1}
+	}
+	return trie[K, V]{root: root, size: t.size}
+}
+
+// each calls function for each key and value of trie in unspecified order
+func (t trie[K, V]) each(function func(key K, value V)) {
+	if t.root != nil {
+		t.root.each(function)
+	}
+}
+
+// with returns a copy of node with value set for key with hash, reports whether key was added
+func (n *trieNode[K, V]) with(shift int, hash uint64, key K, value V) (*trieNode[K, V], bool) {
+	bit := uint32(1) << ((hash >> shift) & trieMask)
+	index := bits.OnesCount32(n.bitmap & (bit - 1))
+	item := trieItem[K, V]{key: key, value: value}
+
+	if n.bitmap&bit == 0 {
+		entries := make([]trieEntry[K, V], len(n.entries)+1)
+		copy(entries, n.entries[:index])
+		entries[index] = trieEntry[K, V]{leaf: &trieLeaf[K, V]{hash: hash, items: []trieItem[K, V]{item}}}
+		copy(entries[index+1:], n.entries[index:])
+		return &trieNode[K, V]{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	entries := append([]trieEntry[K, V](nil), n.entries...)
+	entry := entries[index]
+	added := true
+	switch {
+	case entry.node != nil:
+		entries[index].node, added = entry.node.with(shift+trieBits, hash, key, value)
+	case entry.leaf.hash == hash:
+		items := append([]trieItem[K, V](nil), entry.leaf.items...)
+		for i := range items {
+			if items[i].key == key {
+				items[i] = item
+				added = false
+				break
+			}
+		}
+		if added {
+			items = append(items, item)
+		}
+		entries[index].leaf = &trieLeaf[K, V]{hash: hash, items: items}
+	default:
+		next := &trieNode[K, V]{
+			bitmap:  uint32(1) << ((entry.leaf.hash >> (shift + trieBits)) & trieMask),
+			entries: []trieEntry[K, V]{entry},
+		}
+		next, _ = next.with(shift+trieBits, hash, key, value)
+		entries[index] = trieEntry[K, V]{node: next}
+	}
+
+	return &trieNode[K, V]{bitmap: n.bitmap, entries: entries}, added
+}
+
+// each calls function for each key and value of node and its children
+func (n *trieNode[K, V]) each(function func(key K, value V)) {
+	for _, entry := range n.entries {
+		if entry.node != nil {
+			entry.node.each(function)
+			continue
+		}
+		for _, item := range entry.leaf.items {
+			function(item.key, item.value)
+		}
+	}
+}
+
+// hashString returns hash of string
+func hashString(s string) uint64 {
+	return maphash.String(hashSeed, s)
+}
@@ -0,0 +1,74 @@
+package mdi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTrie(t *testing.T) {
+	t.Run("persistent", func(t *testing.T) {
+		var versions []trie[int, int]
+		var tr trie[int, int]
+		for i := 0; i < 1000; i++ {
+			versions = append(versions, tr)
+			tr = tr.with(hashString(fmt.Sprint(i)), i, i*2)
+		}
+
+		if tr.len() != 1000 {
+			t.Fatalf("unexpected: %d", tr.len())
+		}
+		for i, version := range versions {
+			if version.len() != i {
+				t.Fatalf("unexpected: %d", version.len())
+			}
+			if _, ok := version.get(hashString(fmt.Sprint(i)), i); ok {
+				t.Fatalf("unexpected key: %d", i)
+			}
+			if i > 0 {
+				if value, ok := version.get(hashString(fmt.Sprint(i-1)), i-1); !ok || value != (i-1)*2 {
+					t.Fatalf("unexpected: %d", value)
+				}
+			}
+		}
+
+		sum := 0
+		tr.each(func(key, value int) { sum += value })
+		if sum != 999*1000 {
+			t.Fatalf("unexpected: %d", sum)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		tr := trie[string, int]{}.with(1, "a", 1)
+		replaced := tr.with(1, "a", 2)
+		if replaced.len() != 1 {
+			t.Fatalf("unexpected: %d", replaced.len())
+		}
+		if value, _ := replaced.get(1, "a"); value != 2 {
+			t.Fatalf("unexpected: %d", value)
+		}
+		if value, _ := tr.get(1, "a"); value != 1 {
+			t.Fatalf("unexpected: %d", value)
+		}
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		hashes := []uint64{0, 1 << 60, 1<<60 | 1, 0, 1 << 60}
+		var tr trie[int, int]
+		for i, hash := range hashes {
+			tr = tr.with(hash, i, i)
+		}
+
+		if tr.len() != len(hashes) {
+			t.Fatalf("unexpected: %d", tr.len())
+		}
+		for i, hash := range hashes {
+			if value, ok := tr.get(hash, i); !ok || value != i {
+				t.Fatalf("unexpected: %d", value)
+			}
+		}
+		if _, ok := tr.get(1, 0); ok {
+			t.Fatal("unexpected key")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package mdi
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ProvideValue adds value provider to container, value is registered under type T (even if T is an interface),
+// unlike [DI.Provide] that uses dynamic type of value
+func ProvideValue[T any](d *DI, value T, options ...ProviderOption) error {
+	return d.provideValue(typeOf[T](), reflect.ValueOf(&value).Elem(), options)
+}
+
+// ProvideFunc adds function provider of type T to container, error returned by constructor will be returned on
+// dependency resolution
+func ProvideFunc[T any](d *DI, constructor func() (T, error), options ...ProviderOption) error {
+	return d.provideFunctionValue(constructor, typeOf[T](), 0, options)
+}
+
+// ProvideGeneric adds function provider of type T to container, constructor can be any function which first non-error
+// return value is assignable to T, generic constructors can't be provided uninstantiated, so instantiate them
+// explicitly, each instantiation is a distinct provider (e.g. ProvideGeneric[*Repo[int]](d, NewRepo[int]) and
+// ProvideGeneric[*Repo[string]](d, NewRepo[string]))
+func ProvideGeneric[T any](d *DI, constructor any, options ...ProviderOption) error {
+	fType := reflect.TypeOf(constructor)
+	if fType == nil || fType.Kind() != reflect.Func {
+		return newErrorNotAFunction()
+	}
+
+	pType := typeOf[T]()
+	for i := 0; i < fType.NumOut(); i++ {
+		out := fType.Out(i)
+		if isTypeErr(out) {
+			continue
+		}
+		if !out.AssignableTo(pType) {
+			return fmt.Errorf("can't provide value of type %q as type %q", out.String(), pType.String())
+		}
+		return d.provideFunctionValue(constructor, pType, i, options)
+	}
+	return fmt.Errorf("can't add func provider %q without return values", fType.String())
+}
+
+// Resolve returns dependency of type T from container, errors of constructors are wrapped, so they can be unwrapped
+// using [errors.Is] or [errors.As]
+func Resolve[T any](d *DI) (T, error) {
+	var value T
+	result, err := d.invokeParam(typeOf[T](), 0, newResolution(d))
+	if err != nil {
+		return value, err
+	}
+
+	reflect.ValueOf(&value).Elem().Set(result)
+	return value, nil
+}
+
+// ResolveByKey is like [Resolve], but returns dependency of type T registered with custom key, see [WithKeyFunc]
+func ResolveByKey[T any](d *DI, key any) (T, error) {
+	var value T
+	if key != nil && !reflect.TypeOf(key).Comparable() {
+		return value, fmt.Errorf("can't use custom key of type %T, must be comparable", key)
+	}
+
+	result, err := d.invokeParamKey(providerKey{pType: typeOf[T](), custom: key}, 0, newResolution(d))
+	if err != nil {
+		return value, err
+	}
+
+	reflect.ValueOf(&value).Elem().Set(result)
+	return value, nil
+}
+
+// ResolveTagged is like [Resolve], but returns dependency of the only provider of type T (with any name or key) that
+// has the tag, returns an error if there are no such providers or there are several of them, see [WithTags]
+func ResolveTagged[T any](d *DI, tag string) (T, error) {
+	var value T
+	key := providerKey{pType: typeOf[T]()}
+
+	var matches []providerKey
+	var match *provider
+	var matchOwner *DI
+	seen := make(map[providerKey]bool)
+	for di := d; di != nil; di = di.parent {
+		providers := di.providers()
+		for _, pKey := range sortedKeys(providers) {
+			p, _ := providers.get(pKey)
+			if pKey.pType != key.pType || seen[pKey] || !p.isActive() {
+				continue
+			}
+			seen[pKey] = true
+			if slices.Contains(p.tags, tag) {
+				matches = append(matches, pKey)
+				match, matchOwner = p, di
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return value, fmt.Errorf("tag %q: %w", tag, newErrorProviderNotFound(0, key))
+	case 1:
+	default:
+		return value, fmt.Errorf("tag %q: %w", tag, newErrorAmbiguousProvider(0, key, matches))
+	}
+
+	result, err := d.provideParam(matches[0], match, matchOwner, 0, newResolution(d))
+	if err != nil {
+		return value, err
+	}
+
+	reflect.ValueOf(&value).Elem().Set(result)
+	return value, nil
+}
+
+// MustResolve is like [Resolve], but panics if error occurs
+func MustResolve[T any](d *DI) T {
+	value, err := Resolve[T](d)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ResolveCycler returns function that returns the next element of round-robin provider of type T on each call, it
+// shares position with other resolutions of the provider (see [WithRoundRobin]), collection of function provider is
+// constructed before returning, so returned function panics only if collection has to be constructed again and it
+// fails (e.g. after reset)
+func ResolveCycler[T any](d *DI) (func() T, error) {
+	key := providerKey{pType: typeOf[T]()}
+	p, owner, ok := d.lookupProvider(key)
+	if !ok {
+		return nil, newErrorProviderNotFound(0, key)
+	}
+	if !p.useRoundRobin {
+		return nil, fmt.Errorf("can't cycle %s, provider is not round-robin", key.describe())
+	}
+	if p.isFunction() {
+		if _, err := p.construct(owner, newResolution(d, key)); err != nil {
+			return nil, err
+		}
+	}
+
+	return func() T {
+		result, err := d.provideParam(key, p, owner, 0, newResolution(d))
+		if err != nil {
+			panic(err)
+		}
+
+		var value T
+		reflect.ValueOf(&value).Elem().Set(result)
+		return value
+	}, nil
+}
+
+// ResolveN returns n instances of type T from container, for multi-instance providers (see [WithMultiInstance])
+// provider is called n times, so all instances are distinct, for other providers values of n resolutions are returned
+// and reported as shared, since instances may be the same
+func ResolveN[T any](d *DI, n int) ([]T, bool, error) {
+	if n < 0 {
+		return nil, false, fmt.Errorf("can't resolve negative number %d of instances", n)
+	}
+
+	key := providerKey{pType: typeOf[T]()}
+	p, owner, ok := d.lookupProvider(key)
+	if !ok {
+		return nil, false, newErrorProviderNotFound(0, key)
+	}
+
+	values := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		result, err := d.provideParam(key, p, owner, 0, newResolution(d))
+		if err != nil {
+			return nil, false, err
+		}
+
+		var value T
+		reflect.ValueOf(&value).Elem().Set(result)
+		values = append(values, value)
+	}
+
+	return values, n > 1 && !p.disableCache && !p.useRoundRobin, nil
+}
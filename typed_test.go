@@ -0,0 +1,302 @@
+package mdi
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestProvideValue(t *testing.T) {
+	di := New()
+	if err := ProvideValue(di, 1); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideValue[io.Reader](di, os.Stdin); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideValue(di, 2); !errors.Is(err, ErrProviderAlreadyExists) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+	}
+
+	di.MustInvoke(func(i int, r io.Reader) {
+		if i != 1 {
+			t.Fatalf("unexpected: %d", i)
+		}
+		if r != os.Stdin {
+			t.Fatalf("unexpected: %v", r)
+		}
+	})
+	if HasType[*os.File](di) {
+		t.Fatalf("unexpected dynamic type provider")
+	}
+}
+
+func TestProvideFunc(t *testing.T) {
+	di := New()
+	if err := ProvideFunc(di, func() (string, error) { return "test", nil }); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideFunc(di, func() (int, error) { return 0, errTest }); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+
+	di.MustInvoke(func(s string) {
+		if s != "test" {
+			t.Fatalf("unexpected: %q", s)
+		}
+	})
+	if err := di.Invoke(func(i int) {}); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+
+	err := ProvideFunc(New(), func() (int, error) { return 1, errTest }, WithEagerLoading())
+	if !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+}
+
+type testRepo[T any] struct {
+	items []T
+}
+
+func newTestRepo[T any](items ...T) *testRepo[T] {
+	return &testRepo[T]{items: items}
+}
+
+func TestProvideGeneric(t *testing.T) {
+	di := New().MustProvide([]string{"a", "b"})
+	if err := ProvideGeneric[*testRepo[int]](di, newTestRepo[int]); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideGeneric[*testRepo[string]](di, func(items []string) *testRepo[string] {
+		return newTestRepo(items...)
+	}); err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if err := ProvideGeneric[*testRepo[int]](di, newTestRepo[int]); !errors.Is(err, ErrProviderAlreadyExists) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+	}
+	if err := ProvideGeneric[*testRepo[bool]](di, newTestRepo[int]); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+
+	di.MustInvoke(func(intRepo *testRepo[int], stringRepo *testRepo[string]) {
+		if len(intRepo.items) != 0 {
+			t.Fatalf("unexpected: %v", intRepo.items)
+		}
+		if len(stringRepo.items) != 2 {
+			t.Fatalf("unexpected: %v", stringRepo.items)
+		}
+	})
+}
+
+func TestResolve(t *testing.T) {
+	di := New().
+		MustProvide(func() (int, error) { return 1, nil }).
+		MustProvide(func(i int) (string, error) { return "", errTest }).
+		MustProvide(func(s string) float64 { return 0 })
+
+	if i, err := Resolve[int](di); err != nil || i != 1 {
+		t.Fatalf("unexpected: %d %v", i, err)
+	}
+	if i := MustResolve[int](di); i != 1 {
+		t.Fatalf("unexpected: %d", i)
+	}
+
+	if _, err := Resolve[float64](di); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+	if err := di.Invoke(func(f float64) {}); !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+
+	err := di.Provide(func(f float64) bool { return false }, WithEagerLoading())
+	if !errors.Is(err, errTest) {
+		t.Fatalf("expected error: %q, but got: %v", errTest, err)
+	}
+
+	if _, err = Resolve[io.Writer](di); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+}
+
+func TestResolveByKey(t *testing.T) {
+	type regionKey struct {
+		region string
+		zone   int
+	}
+
+	di := New().
+		MustProvide("default").
+		MustProvide("eu-1", WithKeyFunc(func() any { return regionKey{region: "eu", zone: 1} })).
+		MustProvide(func() string { return "us-2" }, WithKeyFunc(func() any { return regionKey{region: "us", zone: 2} }))
+
+	for key, expected := range map[regionKey]string{{region: "eu", zone: 1}: "eu-1", {region: "us", zone: 2}: "us-2"} {
+		value, err := ResolveByKey[string](di, key)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if value != expected {
+			t.Fatalf("unexpected: %q", value)
+		}
+	}
+	if value := MustResolve[string](di); value != "default" {
+		t.Fatalf("unexpected: %q", value)
+	}
+
+	if _, err := ResolveByKey[string](di, regionKey{region: "eu", zone: 2}); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+	}
+	err := di.Provide("duplicate", WithKeyFunc(func() any { return regionKey{region: "eu", zone: 1} }))
+	if !errors.Is(err, ErrProviderAlreadyExists) {
+		t.Fatalf("expected error: %q, but got: %v", ErrProviderAlreadyExists, err)
+	}
+	if err = di.Provide("invalid", WithKeyFunc(func() any { return []int{1} })); err == nil {
+		t.Fatalf("expected error, but got nil")
+	}
+
+	calls := 0
+	di.MustProvide(func() (string, int) { return "ap-3", 3 }, WithKeyFunc(func() any {
+		calls++
+		return regionKey{region: "ap", zone: 3}
+	}))
+	if calls != 1 {
+		t.Fatalf("unexpected: %d", calls)
+	}
+	if value, err := ResolveByKey[int](di, regionKey{region: "ap", zone: 3}); err != nil || value != 3 {
+		t.Fatalf("unexpected: %d, %v", value, err)
+	}
+}
+
+func TestResolveTagged(t *testing.T) {
+	parent := New().MustProvide(3, WithName("c"), WithTags("shared"))
+	di := parent.Scope().
+		MustProvide(1, WithName("a"), WithTags("primary", "shared")).
+		MustProvide(2, WithName("b"), WithTags("secondary"))
+
+	t.Run("success", func(t *testing.T) {
+		if value, err := ResolveTagged[int](di, "primary"); err != nil || value != 1 {
+			t.Fatalf("unexpected: %d, %v", value, err)
+		}
+		if value, err := ResolveTagged[int](di, "secondary"); err != nil || value != 2 {
+			t.Fatalf("unexpected: %d, %v", value, err)
+		}
+		if value, err := ResolveTagged[int](parent, "shared"); err != nil || value != 3 {
+			t.Fatalf("unexpected: %d, %v", value, err)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		if _, err := ResolveTagged[int](di, "unknown"); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+		if _, err := ResolveTagged[string](di, "primary"); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+
+	t.Run("error_ambiguous", func(t *testing.T) {
+		if _, err := ResolveTagged[int](di, "shared"); !errors.Is(err, ErrAmbiguousProvider) {
+			t.Fatalf("expected error: %q, but got: %v", ErrAmbiguousProvider, err)
+		}
+	})
+}
+
+func TestResolveN(t *testing.T) {
+	t.Run("success_multi_instance", func(t *testing.T) {
+		counter := 0
+		di := New().MustProvide(func() *int { counter++; value := counter; return &value }, WithMultiInstance())
+
+		values, shared, err := ResolveN[*int](di, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if shared {
+			t.Fatal("unexpected shared instances")
+		}
+		if len(values) != 3 || *values[0] != 1 || *values[1] != 2 || *values[2] != 3 {
+			t.Fatalf("unexpected: %v", values)
+		}
+	})
+
+	t.Run("shared_instance", func(t *testing.T) {
+		di := New().MustProvide(func() *int { value := 1; return &value })
+
+		values, shared, err := ResolveN[*int](di, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if !shared || len(values) != 2 || values[0] != values[1] {
+			t.Fatalf("unexpected: %v", values)
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		if _, _, err := ResolveN[int](New(), 2); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+}
+
+func TestResolveCycler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		calls := 0
+		di := New().MustProvide(func() []int { calls++; return []int{1, 2, 3} }, WithRoundRobin())
+
+		next, err := ResolveCycler[int](di)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+
+		var values []int
+		for i := 0; i < 7; i++ {
+			values = append(values, next())
+		}
+		if !reflect.DeepEqual(values, []int{1, 2, 3, 1, 2, 3, 1}) {
+			t.Fatalf("unexpected: %v", values)
+		}
+		if calls != 1 {
+			t.Fatalf("unexpected: %d", calls)
+		}
+
+		if value := MustResolve[int](di); value != 2 {
+			t.Fatalf("expected shared position, but got: %d", value)
+		}
+		if value := next(); value != 3 {
+			t.Fatalf("expected shared position, but got: %d", value)
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		di := New().MustProvide([]string{"a", "b"}, WithRoundRobin())
+
+		next, err := ResolveCycler[string](di)
+		if err != nil {
+			t.Fatalf("unexpected error: %q", err)
+		}
+		if values := []string{next(), next(), next()}; !reflect.DeepEqual(values, []string{"a", "b", "a"}) {
+			t.Fatalf("unexpected: %v", values)
+		}
+	})
+
+	t.Run("error_constructor", func(t *testing.T) {
+		di := New().MustProvide(func() ([]int, error) { return nil, errTest }, WithRoundRobin())
+		if _, err := ResolveCycler[int](di); !errors.Is(err, errTest) {
+			t.Fatalf("expected error: %q, but got: %v", errTest, err)
+		}
+	})
+
+	t.Run("error_not_round_robin", func(t *testing.T) {
+		if _, err := ResolveCycler[int](New().MustProvide(1)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("error_not_found", func(t *testing.T) {
+		if _, err := ResolveCycler[int](New()); !errors.Is(err, ErrProviderNotFound) {
+			t.Fatalf("expected error: %q, but got: %v", ErrProviderNotFound, err)
+		}
+	})
+}